@@ -5,16 +5,16 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/sirupsen/logrus"
-	"gopkg.in/yaml.v2"
 
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq" //import postgres driver
@@ -22,24 +22,57 @@ import (
 
 // ServerConfig is config struct for server
 type ServerConfig struct {
-	Addr    string `yaml:"listen_addr" envconfig:"LISTEN_ADDR"  default:":8080"`
+	Addr    string `yaml:"listen_addr" envconfig:"LISTEN_ADDR"`
 	Timeout struct {
-		// graceful shutdown
-		Server time.Duration `yaml:"server" default:10`
+		// graceful shutdown, how long in-flight requests may take to finish
+		Server time.Duration `yaml:"server"`
 		// write operation
-		Write time.Duration `yaml:"write" default:10`
+		Write time.Duration `yaml:"write"`
 		// read operation
-		Read time.Duration `yaml:"read" default:10`
+		Read time.Duration `yaml:"read"`
 		// time until idle session is closed
-		Idle time.Duration `yaml:"idle" default:10`
+		Idle time.Duration `yaml:"idle"`
 	} `yaml:"timeout"`
 }
 
+// Server defaults, used for the settings left empty in the config
+const (
+	defaultListenAddr    = ":8080"
+	defaultServerTimeout = 10 * time.Second
+)
+
+// setDefaults fills the empty address and timeouts
+func (c *ServerConfig) setDefaults() {
+	if c.Addr == "" {
+		c.Addr = defaultListenAddr
+	}
+	for _, timeout := range c.timeouts() {
+		if *timeout.value == 0 {
+			*timeout.value = defaultServerTimeout
+		}
+	}
+}
+
+type namedTimeout struct {
+	name  string
+	value *time.Duration
+}
+
+func (c *ServerConfig) timeouts() []namedTimeout {
+	return []namedTimeout{
+		{"server", &c.Timeout.Server},
+		{"write", &c.Timeout.Write},
+		{"read", &c.Timeout.Read},
+		{"idle", &c.Timeout.Idle},
+	}
+}
+
 // DbConfig is configu struct for database
 type DbConfig struct {
-	User     string `yaml:"user" envconfig:"DB_USER"`
-	Password string `yaml:"password" envconfig:"DB_PASSWORD"`
-	DbName   string `yaml:"name" envconfig:"DB_NAME"`
+	User         string `yaml:"user" envconfig:"DB_USER"`
+	Password     string `yaml:"password" envconfig:"DB_PASSWORD" secret:"true"`
+	PasswordFile string `yaml:"password_file" envconfig:"DB_PASSWORD_FILE"`
+	DbName       string `yaml:"name" envconfig:"DB_NAME"`
 }
 
 // GetDatabase creates database connection using postgres driver
@@ -50,35 +83,65 @@ func (c *DbConfig) GetDatabase() (*sql.DB, error) {
 	return sql.Open("postgres", dbinfo)
 }
 
+// dbExecutor is implemented by both *sql.DB and *sql.Tx
+type dbExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 // Config is struct for both database and server configuration
 type Config struct {
 	AppName      string
-	ServerConfig ServerConfig `yaml:"server"`
-	DBConfig     DbConfig     `yaml:"database"`
+	ServerConfig ServerConfig       `yaml:"server"`
+	DBConfig     DbConfig           `yaml:"database"`
+	Notification NotificationConfig `yaml:"notification"`
+	Spam         SpamConfig         `yaml:"spam"`
+	Auth         AuthConfig         `yaml:"auth"`
+	Content      ContentConfig      `yaml:"content"`
+	Feed         FeedConfig         `yaml:"feed"`
+	Trash        TrashConfig        `yaml:"trash"`
+	Validation   ValidationConfig   `yaml:"validation"`
+	Log          LogConfig          `yaml:"log"`
+	CORS         CORSConfig         `yaml:"cors"`
+
+	// path is the file the config was read from, read again on reload
+	path string
 }
 
 // NewConfig creates a new config from yaml file
-// It first reads from config.yaml file. It overrides values from environment values
+// It first reads from config.yaml file, then from config.{APP_ENV}.yaml when APP_ENV is set.
+// It overrides values from environment values and reads secrets from their files.
+// Without a path the config comes from environment values only. The result is validated.
+// readServiceConfig of cmd/cerci reads the database settings the same way.
 func NewConfig(configPath string) (*Config, error) {
-	logrus.WithField("configPath", configPath).Info("reading from config path")
-	config := &Config{}
-	file, err := os.Open(configPath)
+	config := &Config{path: configPath}
+	if configPath != "" {
+		paths := []string{configPath}
+		if env := os.Getenv("APP_ENV"); env != "" {
+			paths = append(paths, overlayPath(configPath, env))
+		}
+		for _, path := range paths {
+			logrus.WithField("configPath", path).Info("reading from config path")
+			if err := decodeConfigFile(path, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	logrus.Info("Reading environment variables")
+	err := envconfig.Process("", config)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	decoder := yaml.NewDecoder(file)
-	if err := decoder.Decode(&config); err != nil {
+	if err = loadSecretFiles(reflect.ValueOf(config).Elem(), ""); err != nil {
 		return nil, err
 	}
 
-	logrus.Info("Reading environment variables")
-	err = envconfig.Process("", config)
-	if err != nil {
+	config.ServerConfig.setDefaults()
+	if err = config.Validate(); err != nil {
 		return nil, err
 	}
-
 	return config, nil
 }
 
@@ -88,32 +151,56 @@ type App struct {
 	db           *sql.DB
 	Router       *mux.Router
 	ShutdownHook func()
+
+	notifier  *Notifier
+	spamGuard *SpamGuard
+	cors      *corsPolicy
+
+	workerCtx     context.Context
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
 }
 
 // NewApp creates a new instance of App
 func NewApp(conf *Config) *App {
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
 	return &App{
-		conf:   conf,
-		Router: mux.NewRouter(),
+		conf:          conf,
+		Router:        mux.NewRouter(),
+		workerCtx:     workerCtx,
+		cancelWorkers: cancelWorkers,
 	}
 }
 
-// AddRoute adds a route to applicatoin
+// AddRoute adds a route to applicatoin, its responses are negotiated between JSON, XML and CSV
 func (app *App) AddRoute(method string, route string, apiHandler func(w http.ResponseWriter, r *http.Request)) {
+	app.Router.HandleFunc(route, app.negotiate(apiHandler)).Methods(method)
+}
+
+// AddRawRoute adds a route whose handler writes its own content type, like feeds and images
+func (app *App) AddRawRoute(method string, route string, apiHandler func(w http.ResponseWriter, r *http.Request)) {
 	app.Router.HandleFunc(route, apiHandler).Methods(method)
 }
 
-// RenderJson creates a json response of the data
+// RenderJson creates a json response of the data, or an XML or CSV response
+// when that is what the client negotiated
 func (app *App) RenderJson(writer http.ResponseWriter, status int, data interface{}) {
+	if negotiated, ok := writer.(*negotiatedWriter); ok && negotiated.format != MediaJSON && data != nil {
+		app.renderNegotiated(writer, status, negotiated.format, data)
+		return
+	}
 	writer.Header().Set("Content-Type", "application/json")
-	if data != nil {
-		jsonData, err := json.Marshal(data)
-		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		writer.Write(jsonData)
+	if data == nil {
+		writer.WriteHeader(status)
+		return
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	writer.WriteHeader(status)
+	writer.Write(jsonData)
 }
 
 // RenderError render error cases
@@ -128,50 +215,64 @@ func (app *App) RenderErrorResponse(writer http.ResponseWriter, httpStatus int,
 	app.RenderError(writer, ErrorResponse{Status: httpStatus, Error: err, Message: message})
 }
 
+// runServer serves until SIGINT or SIGTERM, then shuts down in order: the
+// server stops accepting connections and waits up to Timeout.Server for the
+// in-flight requests, the background workers stop and the database closes.
+// A second signal ends the wait for in-flight requests. SIGHUP reloads the
+// config.
 func (app *App) runServer() error {
-	var runChan = make(chan os.Signal, 1)
-	ctx, cancel := context.WithTimeout(
-		context.Background(),
-		app.conf.ServerConfig.Timeout.Server,
-	)
-	defer cancel()
-
+	timeout := app.conf.ServerConfig.Timeout
 	server := &http.Server{
 		Addr:         app.conf.ServerConfig.Addr,
-		Handler:      app.Router,
-		ReadTimeout:  app.conf.ServerConfig.Timeout.Read * time.Second,
-		WriteTimeout: app.conf.ServerConfig.Timeout.Write * time.Second,
-		IdleTimeout:  app.conf.ServerConfig.Timeout.Idle * time.Second,
+		Handler:      app.cors.handler(app.Router),
+		ReadTimeout:  timeout.Read,
+		WriteTimeout: timeout.Write,
+		IdleTimeout:  timeout.Idle,
 	}
 
-	// Handle ctrl+c/ctrl+x interrupt
-	signal.Notify(runChan, os.Interrupt, syscall.SIGTSTP)
-
-	// Alert the user that the server is starting
-	logrus.WithField("address", server.Addr).Info("Staring server...")
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
 
-	// Run the server on a new goroutine
+	logrus.WithField("address", server.Addr).Info("Starting server...")
+	serverErr := make(chan error, 1)
 	go func() {
-		if err := server.ListenAndServe(); err != nil {
-			if err == http.ErrServerClosed {
-				// Normal interrupt operation, ignore
-			} else {
-				logrus.Fatalf("Server failed to start due to err: %v", err)
-			}
-		}
+		serverErr <- server.ListenAndServe()
 	}()
 
-	// Block on this channel listeninf for those previously defined syscalls assign
-	// to variable so we can let the user know why the server is shutting down
-	interrupt := <-runChan
+	for stopped := false; !stopped; {
+		select {
+		case err := <-serverErr:
+			app.ShutdownHook()
+			return fmt.Errorf("server failed: %v", err)
+		case <-reload:
+			app.reloadConfig()
+		case sig := <-signals:
+			logrus.WithField("signal", sig.String()).WithField("grace_period", timeout.Server.String()).
+				Info("Server is shutting down, draining in-flight requests")
+			stopped = true
+		}
+	}
 
-	// If we get one of the pre-prescribed syscalls, gracefully terminate the server
-	// while alerting the user
-	log.Printf("Server is shutting down due to %+v\n", interrupt)
-	app.ShutdownHook()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Server)
+	defer cancel()
+	go func() {
+		select {
+		case sig := <-signals:
+			logrus.WithField("signal", sig.String()).Warn("Second signal, closing open connections")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 	if err := server.Shutdown(ctx); err != nil {
-		logrus.Fatalf("Server was unable to gracefully shutdown due to err: %+v", err)
+		logrus.WithError(err).Warn("In-flight requests did not finish, closing open connections")
+		server.Close()
 	}
+	app.ShutdownHook()
+	logrus.Info("Server stopped")
 	return nil
 }
 
@@ -184,9 +285,27 @@ func (app *App) Run() error {
 		return fmt.Errorf("failed to get postgres connection: %v", err)
 	}
 
+	app.spamGuard, err = NewSpamGuard(app.conf.Spam)
+	if err != nil {
+		return fmt.Errorf("failed to create spam guard: %v", err)
+	}
+
+	app.cors = newCORSPolicy(app.conf.CORS)
 	app.AddRoutes()
 
+	app.notifier = NewNotifier(app.conf.Notification, app.db, NewSMTPMailer(app.conf.Notification.SMTP))
+	if app.conf.Notification.Enabled {
+		app.startWorker("notification-outbox", app.notifier.conf.PollInterval, app.notifier.ProcessOutbox)
+	}
+	app.startWorker("rate-limit-cleanup", time.Minute, app.spamGuard.cleanupRateLimits)
+	app.startWorker("news-expiry", time.Minute, app.archiveExpiredNews)
+	app.startWorker("trash-purge", app.conf.Trash.purgeInterval(), app.purgeTrash)
+
 	app.ShutdownHook = func() {
+		logrus.Info("Stopping background workers....")
+		if !app.stopWorkers(app.conf.ServerConfig.Timeout.Server) {
+			logrus.Warn("Background workers did not stop in time")
+		}
 		logrus.Info("Closing database connections....")
 		if app != nil && app.db != nil {
 			app.db.Close()
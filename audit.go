@@ -0,0 +1,209 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Audited actions
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditRevert  = "revert"
+)
+
+// Resource types recorded in the audit log
+const (
+	AuditNews               = "news"
+	AuditProject            = "project"
+	AuditJob                = "job"
+	AuditRelation           = "relation"
+	AuditNewsProject        = "news_project"
+	AuditNewsTranslation    = "news_translation"
+	AuditProjectTranslation = "project_translation"
+	AuditTag                = "tag"
+	AuditCategory           = "category"
+	AuditTechnology         = "technology"
+)
+
+// auditOmittedFields are left out of snapshots, images would bloat the log
+var auditOmittedFields = []string{"news_image", "project_images"}
+
+const auditColumns = "id,actor,action,resource_type,resource_id,before,after,COALESCE(request_id,''),created"
+
+// AuditResponse response struct
+//
+//swagger:response AuditResponse
+type AuditResponse struct {
+	ID           int64           `json:"id"`
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	RequestID    string          `json:"request_id"`
+	Created      time.Time       `json:"created"`
+}
+
+// auditEntry is a single change to record
+type auditEntry struct {
+	action       string
+	resourceType string
+	resourceID   interface{}
+	// snapshots of the resource, nil when it did not exist
+	before interface{}
+	after  interface{}
+}
+
+// actorName returns the name recorded as the actor of a request
+func actorName(principal *Principal) string {
+	if principal == nil {
+		return "anonymous"
+	}
+	return principal.Name
+}
+
+// auditSnapshot marshals a snapshot, dropping auditOmittedFields from objects
+func auditSnapshot(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return string(data), nil
+	}
+	for _, name := range auditOmittedFields {
+		delete(fields, name)
+	}
+	data, err = json.Marshal(fields)
+	return string(data), err
+}
+
+// systemActor is the actor of changes made by the background workers
+const systemActor = "system"
+
+// recordAudit writes an audit entry with db, pass the transaction of the
+// change so the entry is only kept when the change is committed
+func (app *App) recordAudit(db dbExecutor, req *http.Request, entry auditEntry) error {
+	return insertAudit(db, actorName(app.authenticate(req)), requestID(req), entry)
+}
+
+// insertAudit writes an audit entry of actor, requestID is empty for changes
+// not made by a request
+func insertAudit(db dbExecutor, actor string, requestID string, entry auditEntry) error {
+	before, err := auditSnapshot(entry.before)
+	if err != nil {
+		return err
+	}
+	after, err := auditSnapshot(entry.after)
+	if err != nil {
+		return err
+	}
+	sql := "INSERT INTO audit_log(actor,action,resource_type,resource_id,before,after,request_id,created) " +
+		"VALUES($1,$2,$3,$4,$5,$6,NULLIF($7,''),$8)"
+	_, err = db.Exec(sql, actor, entry.action, entry.resourceType, fmt.Sprint(entry.resourceID), before, after, requestID, time.Now())
+	return err
+}
+
+// auditLogged records an entry for a change that was not made in a
+// transaction. Failures are only logged since the change already happened.
+func (app *App) auditLogged(req *http.Request, entry auditEntry) {
+	if err := app.recordAudit(app.db, req, entry); err != nil {
+		logrus.WithError(err).WithField("request_id", requestID(req)).Error("Failed to record audit entry")
+	}
+}
+
+// rowSnapshot loads a row as json and locks it for the rest of the transaction
+func rowSnapshot(db dbExecutor, table string, id interface{}) (json.RawMessage, error) {
+	var snapshot []byte
+	err := db.QueryRow("SELECT to_jsonb(x) FROM "+table+" x WHERE uid=$1 FOR UPDATE", id).Scan(&snapshot)
+	return json.RawMessage(snapshot), err
+}
+
+// GetAuditLog lists audit entries, newest first. Supported filters: actor,
+// action, resource_type, resource_id, request_id, since and until (RFC 3339),
+// limit (at most 1000) and before_id for paging.
+func (app *App) GetAuditLog(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireAdmin(writer, req) {
+		return
+	}
+	query := req.URL.Query()
+	var where whereBuilder
+	for _, filter := range []string{"actor", "action", "resource_type", "resource_id", "request_id"} {
+		if value := query.Get(filter); value != "" {
+			where.Add(filter+"=?", value)
+		}
+	}
+	for param, condition := range map[string]string{"since": "created >= ?", "until": "created < ?"} {
+		if value := query.Get(param); value != "" {
+			date, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				app.RenderErrorResponse(writer, http.StatusBadRequest, err, param+" must be an RFC 3339 timestamp")
+				return
+			}
+			where.Add(condition, date)
+		}
+	}
+	if value := query.Get("before_id"); value != "" {
+		beforeID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			app.RenderErrorResponse(writer, http.StatusBadRequest, err, "before_id must be a number")
+			return
+		}
+		where.Add("id < ?", beforeID)
+	}
+	limit := 100
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 1000 {
+			app.RenderErrorResponse(writer, http.StatusBadRequest, fmt.Errorf("invalid limit %q", value), "limit must be between 1 and 1000")
+			return
+		}
+		limit = parsed
+	}
+
+	sql := "SELECT " + auditColumns + " FROM audit_log" + where.SQL() + " ORDER BY id DESC LIMIT " + where.Arg(limit)
+	rows, err := app.db.Query(sql, where.Args()...)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get audit log")
+		return
+	}
+	defer rows.Close()
+
+	entries := []AuditResponse{}
+	for rows.Next() {
+		entry := AuditResponse{}
+		var before, after dbsql.RawBytes
+		err = rows.Scan(&entry.ID, &entry.Actor, &entry.Action, &entry.ResourceType, &entry.ResourceID,
+			&before, &after, &entry.RequestID, &entry.Created)
+		if err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get audit log")
+			return
+		}
+		entry.Before = rawJSON(before)
+		entry.After = rawJSON(after)
+		entries = append(entries, entry)
+	}
+	app.RenderJson(writer, http.StatusOK, entries)
+}
+
+// rawJSON copies a json column, NULL becomes json null
+func rawJSON(value dbsql.RawBytes) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return append(json.RawMessage{}, value...)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	dbsql "database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Roles that can be assigned to api keys
+const (
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// AuthConfig is config struct for api key authentication
+type AuthConfig struct {
+	APIKeys []APIKeyConfig `yaml:"api_keys"`
+}
+
+// APIKeyConfig is a single api key
+type APIKeyConfig struct {
+	Name    string `yaml:"name"`
+	Key     string `yaml:"key" secret:"true"`
+	KeyFile string `yaml:"key_file"`
+	Role    string `yaml:"role"`
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Name string
+	Role string
+}
+
+// IsEditor reports whether the principal may manage content
+func (p *Principal) IsEditor() bool {
+	return p != nil && (p.Role == RoleEditor || p.Role == RoleAdmin)
+}
+
+// IsAdmin reports whether the principal may administer the service
+func (p *Principal) IsAdmin() bool {
+	return p != nil && p.Role == RoleAdmin
+}
+
+// requestAPIKey reads the api key from X-API-Key or a bearer token
+func requestAPIKey(req *http.Request) string {
+	if key := req.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+// HashAPIKey returns the hash stored for keys of the api_key table
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// authenticate returns the principal of the request, nil for anonymous callers.
+// It is resolved once per request by authMiddleware.
+func (app *App) authenticate(req *http.Request) *Principal {
+	if principal, ok := req.Context().Value(principalKey).(*Principal); ok {
+		return principal
+	}
+	return app.resolvePrincipal(req)
+}
+
+// authMiddleware resolves the principal of every request, so handlers that
+// check it several times do not look up the api key again
+func (app *App) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		principal := app.resolvePrincipal(req)
+		next.ServeHTTP(writer, req.WithContext(context.WithValue(req.Context(), principalKey, principal)))
+	})
+}
+
+// resolvePrincipal looks up the api key of the request. Keys of the config
+// are checked first, then the keys created with the cerci command in the
+// api_key table.
+func (app *App) resolvePrincipal(req *http.Request) *Principal {
+	key := requestAPIKey(req)
+	if key == "" {
+		return nil
+	}
+	for _, apiKey := range app.conf.Auth.APIKeys {
+		if apiKey.Key != "" && subtle.ConstantTimeCompare([]byte(apiKey.Key), []byte(key)) == 1 {
+			return &Principal{Name: apiKey.Name, Role: apiKey.Role}
+		}
+	}
+	if app.db == nil {
+		return nil
+	}
+	var principal Principal
+	sql := "SELECT name,role FROM api_key WHERE key_hash=$1 AND revoked_at IS NULL"
+	err := app.db.QueryRow(sql, HashAPIKey(key)).Scan(&principal.Name, &principal.Role)
+	if err != nil {
+		if err != dbsql.ErrNoRows {
+			logrus.WithError(err).Error("Failed to look up api key")
+		}
+		return nil
+	}
+	return &principal
+}
+
+// requireEditor renders an error and returns false unless the caller is an editor
+func (app *App) requireEditor(writer http.ResponseWriter, req *http.Request) bool {
+	principal := app.authenticate(req)
+	if principal == nil {
+		app.RenderErrorResponse(writer, http.StatusUnauthorized, errors.New("missing or invalid api key"), "Authentication required")
+		return false
+	}
+	if !principal.IsEditor() {
+		app.RenderErrorResponse(writer, http.StatusForbidden, errors.New("editor role required"), "Permission denied")
+		return false
+	}
+	return true
+}
+
+// requireAdmin renders an error and returns false unless the caller is an admin
+func (app *App) requireAdmin(writer http.ResponseWriter, req *http.Request) bool {
+	principal := app.authenticate(req)
+	if principal == nil {
+		app.RenderErrorResponse(writer, http.StatusUnauthorized, errors.New("missing or invalid api key"), "Authentication required")
+		return false
+	}
+	if !principal.IsAdmin() {
+		app.RenderErrorResponse(writer, http.StatusForbidden, errors.New("admin role required"), "Permission denied")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// Bulk modes. Atomic commits all operations or none, per item commits the
+// operations that succeed and reports the others.
+const (
+	BulkAtomic  = "atomic"
+	BulkPerItem = "per_item"
+)
+
+// Bulk operation kinds
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// bulkMaxOperations limits the operations of a single bulk request
+const bulkMaxOperations = 500
+
+// NewsBulkOperation request struct. Update and delete need the id and the
+// current version of the item, create and update need the data.
+//
+//swagger:model NewsBulkOperation
+type NewsBulkOperation struct {
+	Op      string       `json:"op"`
+	ID      string       `json:"id,omitempty"`
+	Version *int         `json:"version,omitempty"`
+	Data    *NewsRequest `json:"data,omitempty"`
+}
+
+// ProjectBulkOperation request struct. Update and delete need the id and the
+// current version of the item, create and update need the data.
+//
+//swagger:model ProjectBulkOperation
+type ProjectBulkOperation struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id,omitempty"`
+	Version *int            `json:"version,omitempty"`
+	Data    *ProjectRequest `json:"data,omitempty"`
+}
+
+// RelationBulkOperation request struct. Update only renames a relation,
+// delete removes the relations below it as well.
+//
+//swagger:model RelationBulkOperation
+type RelationBulkOperation struct {
+	Op   string           `json:"op"`
+	ID   string           `json:"id,omitempty"`
+	Data *RelationRequest `json:"data,omitempty"`
+}
+
+// BulkResult is the outcome of one bulk operation
+//
+//swagger:model BulkResult
+type BulkResult struct {
+	Index  int         `json:"index"`
+	Op     string      `json:"op"`
+	ID     string      `json:"id,omitempty"`
+	Status int         `json:"status"`
+	Error  string      `json:"error,omitempty"`
+	Detail string      `json:"detail,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// BulkResponse response struct
+//
+//swagger:response BulkResponse
+type BulkResponse struct {
+	Mode      string       `json:"mode"`
+	Committed bool         `json:"committed"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
+
+// bulkStep runs operation i inside the bulk transaction
+type bulkStep func(tx dbExecutor, i int) (BulkResult, *ErrorResponse)
+
+// readBulkRequest requires an editor and decodes the operations of a bulk request
+func (app *App) readBulkRequest(writer http.ResponseWriter, req *http.Request, operations interface{}) bool {
+	if !app.requireEditor(writer, req) {
+		return false
+	}
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
+		return false
+	}
+	if err = unmarshalRequest(req, reqBody, operations); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Body must be an array of operations")
+		return false
+	}
+	return true
+}
+
+// runBulk runs count operations in one transaction. In atomic mode (the
+// default, ?mode=atomic) the first failure rolls everything back and its status
+// is the response status. In per item mode (?mode=per_item) each operation runs
+// in a savepoint, failed operations are rolled back on their own and reported
+// in the results while the others are committed.
+func (app *App) runBulk(writer http.ResponseWriter, req *http.Request, count int, step bulkStep) {
+	mode := req.URL.Query().Get("mode")
+	if mode == "" {
+		mode = BulkAtomic
+	}
+	if mode != BulkAtomic && mode != BulkPerItem {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, fmt.Errorf("unknown bulk mode %q", mode), "mode must be atomic or per_item")
+		return
+	}
+	if count == 0 || count > bulkMaxOperations {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, fmt.Errorf("%d operations", count),
+			fmt.Sprintf("Send between 1 and %d operations", bulkMaxOperations))
+		return
+	}
+
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to run bulk operations")
+		return
+	}
+	defer tx.Rollback()
+
+	response := BulkResponse{Mode: mode, Results: make([]BulkResult, 0, count)}
+	status := http.StatusOK
+	for i := 0; i < count; i++ {
+		if mode == BulkPerItem {
+			if _, err = tx.Exec("SAVEPOINT bulk_item"); err != nil {
+				app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to run bulk operations")
+				return
+			}
+		}
+		result, ex := step(tx, i)
+		result.Index = i
+		if ex == nil {
+			response.Succeeded++
+			if mode == BulkPerItem {
+				_, err = tx.Exec("RELEASE SAVEPOINT bulk_item")
+			}
+		} else {
+			response.Failed++
+			result.Status, result.Error, result.Data = ex.Status, ex.Message, nil
+			if conflict, ok := ex.Error.(versionConflict); ok {
+				result.Data = map[string]int{"version": conflict.version}
+			}
+			if ex.Status < http.StatusInternalServerError && ex.Error != nil {
+				result.Detail = ex.Error.Error()
+			}
+			if mode == BulkPerItem {
+				_, err = tx.Exec("ROLLBACK TO SAVEPOINT bulk_item; RELEASE SAVEPOINT bulk_item")
+			}
+		}
+		if err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to run bulk operations")
+			return
+		}
+		response.Results = append(response.Results, result)
+		if ex != nil && mode == BulkAtomic {
+			status = ex.Status
+			break
+		}
+	}
+
+	if status == http.StatusOK {
+		if err = tx.Commit(); err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to run bulk operations")
+			return
+		}
+		response.Committed = true
+	}
+	app.RenderJson(writer, status, response)
+}
+
+// bulkIfMatch turns the version of a bulk operation into an If-Match value
+func bulkIfMatch(version *int) string {
+	if version == nil {
+		return ""
+	}
+	return versionETag(*version, "")
+}
+
+// bulkInvalid is the error of an operation that cannot be run
+func bulkInvalid(message string) *ErrorResponse {
+	return &ErrorResponse{Status: http.StatusBadRequest, Error: errors.New(message), Message: message}
+}
+
+// checkBulkOperation validates the op, id and data of an operation
+func checkBulkOperation(op string, id string, hasData bool) *ErrorResponse {
+	switch op {
+	case BulkCreate:
+		if id != "" {
+			return bulkInvalid("create does not take an id")
+		}
+	case BulkUpdate, BulkDelete:
+		if id == "" {
+			return bulkInvalid(op + " needs an id")
+		}
+	default:
+		return bulkInvalid(fmt.Sprintf("unknown op %q, use create, update or delete", op))
+	}
+	if op != BulkDelete && !hasData {
+		return bulkInvalid(op + " needs data")
+	}
+	return nil
+}
+
+// BulkNews runs create, update and delete operations on news items in one transaction
+func (app *App) BulkNews(writer http.ResponseWriter, req *http.Request) {
+	var operations []NewsBulkOperation
+	if !app.readBulkRequest(writer, req, &operations) {
+		return
+	}
+	app.runBulk(writer, req, len(operations), func(tx dbExecutor, i int) (BulkResult, *ErrorResponse) {
+		operation := operations[i]
+		result := BulkResult{Op: operation.Op, ID: operation.ID, Status: http.StatusOK}
+		if ex := checkBulkOperation(operation.Op, operation.ID, operation.Data != nil); ex != nil {
+			return result, ex
+		}
+		if operation.Data != nil {
+			if status, message, err := operation.Data.ValidateNews(); err != nil {
+				return result, &ErrorResponse{Status: status, Error: err, Message: message}
+			}
+		}
+
+		var ex *ErrorResponse
+		switch operation.Op {
+		case BulkCreate:
+			var news NewsResponse
+			news, ex = app.createNews(tx, req, *operation.Data)
+			if ex == nil {
+				result.Status, result.ID, result.Data = http.StatusCreated, fmt.Sprint(news.ID), news
+			}
+		case BulkUpdate:
+			result.Data, ex = app.updateNews(tx, req, operation.ID, bulkIfMatch(operation.Version), *operation.Data)
+		case BulkDelete:
+			ex = app.trashTransition(tx, req, trashNews, operation.ID, bulkIfMatch(operation.Version), AuditDelete, true,
+				softDeleteSQL(trashNews), app.authenticate(req).Name)
+		}
+		return result, ex
+	})
+}
+
+// BulkProjects runs create, update and delete operations on project items in one transaction
+func (app *App) BulkProjects(writer http.ResponseWriter, req *http.Request) {
+	var operations []ProjectBulkOperation
+	if !app.readBulkRequest(writer, req, &operations) {
+		return
+	}
+	app.runBulk(writer, req, len(operations), func(tx dbExecutor, i int) (BulkResult, *ErrorResponse) {
+		operation := operations[i]
+		result := BulkResult{Op: operation.Op, ID: operation.ID, Status: http.StatusOK}
+		if ex := checkBulkOperation(operation.Op, operation.ID, operation.Data != nil); ex != nil {
+			return result, ex
+		}
+		if operation.Data != nil {
+			if status, message, err := operation.Data.ValidateProject(); err != nil {
+				return result, &ErrorResponse{Status: status, Error: err, Message: message}
+			}
+		}
+
+		var ex *ErrorResponse
+		switch operation.Op {
+		case BulkCreate:
+			var project ProjectResponse
+			project, ex = app.createProject(tx, req, *operation.Data)
+			if ex == nil {
+				result.Status, result.ID, result.Data = http.StatusCreated, fmt.Sprint(project.ID), project
+			}
+		case BulkUpdate:
+			result.Data, ex = app.updateProject(tx, req, operation.ID, bulkIfMatch(operation.Version), *operation.Data)
+		case BulkDelete:
+			ex = app.trashTransition(tx, req, trashProjects, operation.ID, bulkIfMatch(operation.Version), AuditDelete, true,
+				softDeleteSQL(trashProjects), app.authenticate(req).Name)
+		}
+		return result, ex
+	})
+}
+
+// BulkRelations runs create, update and delete operations on relations in one transaction
+func (app *App) BulkRelations(writer http.ResponseWriter, req *http.Request) {
+	var operations []RelationBulkOperation
+	if !app.readBulkRequest(writer, req, &operations) {
+		return
+	}
+	app.runBulk(writer, req, len(operations), func(tx dbExecutor, i int) (BulkResult, *ErrorResponse) {
+		operation := operations[i]
+		result := BulkResult{Op: operation.Op, ID: operation.ID, Status: http.StatusOK}
+		if ex := checkBulkOperation(operation.Op, operation.ID, operation.Data != nil); ex != nil {
+			return result, ex
+		}
+
+		var ex *ErrorResponse
+		switch operation.Op {
+		case BulkCreate:
+			var relation RelationResponse
+			relation, ex = app.createRelation(tx, req, *operation.Data)
+			if ex == nil {
+				result.Status, result.ID, result.Data = http.StatusCreated, string(relation.ID), relation
+			}
+		case BulkUpdate:
+			result.Data, ex = app.updateRelation(tx, req, operation.ID, *operation.Data)
+		case BulkDelete:
+			ex = app.deleteRelation(tx, req, operation.ID)
+		}
+		return result, ex
+	})
+}
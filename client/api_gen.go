@@ -0,0 +1,575 @@
+// Code generated by go run ./cmd/openapi; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// AddProjectItem calls POST /project: add new ProjectItem to database and
+// creates a json response of the data. Requires an API key or token.
+func (c *Client) AddProjectItem(ctx context.Context, body ProjectRequest, opts ...RequestOption) (*ProjectResponse, error) {
+	var out ProjectResponse
+	if err := c.call(ctx, request{method: "POST", path: "/project", body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProjectItems calls GET /project: fetches project items from database and
+// creates a json response of the data. Query parameters: lang, status, client,
+// technology, member, role, city, country, near, radius.
+func (c *Client) GetProjectItems(ctx context.Context, query url.Values, opts ...RequestOption) ([]ProjectResponse, error) {
+	var out []ProjectResponse
+	err := c.call(ctx, request{method: "GET", path: "/project", query: query}, &out, opts)
+	return out, err
+}
+
+// BulkProjects calls POST /project/bulk: runs create, update and delete
+// operations on project items in one transaction. Query parameters: mode.
+// Requires an API key or token.
+func (c *Client) BulkProjects(ctx context.Context, body []ProjectBulkOperation, query url.Values, opts ...RequestOption) (*BulkResponse, error) {
+	var out BulkResponse
+	if err := c.call(ctx, request{method: "POST", path: "/project/bulk", query: query, body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProjectTimeline calls GET /project/timeline: returns the projects grouped
+// by the year they started in. Query parameters: lang, status, client,
+// technology, member, role, city, country, near, radius.
+func (c *Client) GetProjectTimeline(ctx context.Context, query url.Values, opts ...RequestOption) ([]TimelineYearResponse, error) {
+	var out []TimelineYearResponse
+	err := c.call(ctx, request{method: "GET", path: "/project/timeline", query: query}, &out, opts)
+	return out, err
+}
+
+// FindProjectItemBySlug calls GET /project/by-slug/{slug}: finds project item
+// by its slug. Query parameters: lang.
+func (c *Client) FindProjectItemBySlug(ctx context.Context, slug string, query url.Values, opts ...RequestOption) (*ProjectResponse, error) {
+	var out ProjectResponse
+	if err := c.call(ctx, request{method: "GET", path: "/project/by-slug/" + url.PathEscape(slug), query: query}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FindProjectItem calls GET /project/{id}: finds project item from database and
+// creates a json response of the data. Query parameters: lang.
+func (c *Client) FindProjectItem(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*ProjectResponse, error) {
+	var out ProjectResponse
+	if err := c.call(ctx, request{method: "GET", path: "/project/" + url.PathEscape(id), query: query}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateProjectItem calls PUT /project/{id}: updates a project item. Requires
+// an API key or token.
+func (c *Client) UpdateProjectItem(ctx context.Context, id string, body ProjectRequest, opts ...RequestOption) (*ProjectResponse, error) {
+	var out ProjectResponse
+	if err := c.call(ctx, request{method: "PUT", path: "/project/" + url.PathEscape(id), body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProjectTranslations calls GET /project/{id}/translations: lists the
+// translations of a project item.
+func (c *Client) GetProjectTranslations(ctx context.Context, id string, opts ...RequestOption) ([]TranslationResponse, error) {
+	var out []TranslationResponse
+	err := c.call(ctx, request{method: "GET", path: "/project/" + url.PathEscape(id) + "/translations"}, &out, opts)
+	return out, err
+}
+
+// PutProjectTranslation calls PUT /project/{id}/translations/{locale}: adds or
+// updates the translation of a project item. Requires an API key or token.
+func (c *Client) PutProjectTranslation(ctx context.Context, id string, locale string, body ProjectTranslationRequest, opts ...RequestOption) (*TranslationResponse, error) {
+	var out TranslationResponse
+	if err := c.call(ctx, request{method: "PUT", path: "/project/" + url.PathEscape(id) + "/translations/" + url.PathEscape(locale), body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteProjectTranslation calls DELETE /project/{id}/translations/{locale}:
+// deletes the translation of a project item. Requires an API key or token.
+func (c *Client) DeleteProjectTranslation(ctx context.Context, id string, locale string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/project/" + url.PathEscape(id) + "/translations/" + url.PathEscape(locale)}, nil, opts)
+}
+
+// LinkNewsToProject calls PUT /project/{id}/news/{news_id}: links a news item
+// to a project. Requires an API key or token.
+func (c *Client) LinkNewsToProject(ctx context.Context, id string, newsID string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "PUT", path: "/project/" + url.PathEscape(id) + "/news/" + url.PathEscape(newsID)}, nil, opts)
+}
+
+// UnlinkNewsFromProject calls DELETE /project/{id}/news/{news_id}: removes the
+// link between a project and a news item. Requires an API key or token.
+func (c *Client) UnlinkNewsFromProject(ctx context.Context, id string, newsID string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/project/" + url.PathEscape(id) + "/news/" + url.PathEscape(newsID)}, nil, opts)
+}
+
+// DeleteProjectItem calls DELETE /project/{id}: moves a project item to the
+// trash and creates a json response of the data. Requires an API key or token.
+func (c *Client) DeleteProjectItem(ctx context.Context, id string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/project/" + url.PathEscape(id)}, nil, opts)
+}
+
+// GetProjectRevisions calls GET /project/{id}/revisions: lists the revisions of
+// a project item. Requires an API key or token.
+func (c *Client) GetProjectRevisions(ctx context.Context, id string, opts ...RequestOption) ([]RevisionResponse, error) {
+	var out []RevisionResponse
+	err := c.call(ctx, request{method: "GET", path: "/project/" + url.PathEscape(id) + "/revisions"}, &out, opts)
+	return out, err
+}
+
+// GetProjectRevisionDiff calls GET /project/{id}/revisions/diff: compares two
+// revisions of a project item. Query parameters: to, from. Requires an API key
+// or token.
+func (c *Client) GetProjectRevisionDiff(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*RevisionDiffResponse, error) {
+	var out RevisionDiffResponse
+	if err := c.call(ctx, request{method: "GET", path: "/project/" + url.PathEscape(id) + "/revisions/diff", query: query}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProjectRevision calls GET /project/{id}/revisions/{rev:[0-9]+}: returns a
+// single revision of a project item. Requires an API key or token.
+func (c *Client) GetProjectRevision(ctx context.Context, id string, rev string, opts ...RequestOption) (*RevisionResponse, error) {
+	var out RevisionResponse
+	if err := c.call(ctx, request{method: "GET", path: "/project/" + url.PathEscape(id) + "/revisions/" + url.PathEscape(rev)}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevertProjectItem calls POST /project/{id}/revisions/{rev:[0-9]+}/revert:
+// restores a project item to one of its revisions. Requires an API key or
+// token.
+func (c *Client) RevertProjectItem(ctx context.Context, id string, rev string, opts ...RequestOption) (*RevisionResponse, error) {
+	var out RevisionResponse
+	if err := c.call(ctx, request{method: "POST", path: "/project/" + url.PathEscape(id) + "/revisions/" + url.PathEscape(rev) + "/revert"}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreProjectItem calls POST /project/{id}/restore: restores a deleted
+// project item. Requires an API key or token.
+func (c *Client) RestoreProjectItem(ctx context.Context, id string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "POST", path: "/project/" + url.PathEscape(id) + "/restore"}, nil, opts)
+}
+
+// AddNewsItem calls POST /news: adds news item to database and creates a json
+// response of the data. Requires an API key or token.
+func (c *Client) AddNewsItem(ctx context.Context, body NewsRequest, opts ...RequestOption) (*NewsResponse, error) {
+	var out NewsResponse
+	if err := c.call(ctx, request{method: "POST", path: "/news", body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNewsItems calls GET /news: gets all news items from database and creates a
+// json response of the data. Query parameters: lang, tag, category, status.
+func (c *Client) GetNewsItems(ctx context.Context, query url.Values, opts ...RequestOption) ([]NewsResponse, error) {
+	var out []NewsResponse
+	err := c.call(ctx, request{method: "GET", path: "/news", query: query}, &out, opts)
+	return out, err
+}
+
+// BulkNews calls POST /news/bulk: runs create, update and delete operations on
+// news items in one transaction. Query parameters: mode. Requires an API key or
+// token.
+func (c *Client) BulkNews(ctx context.Context, body []NewsBulkOperation, query url.Values, opts ...RequestOption) (*BulkResponse, error) {
+	var out BulkResponse
+	if err := c.call(ctx, request{method: "POST", path: "/news/bulk", query: query, body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNewsRSS calls GET /news/feed.rss: renders the published news as an RSS 2.0
+// feed. Query parameters: lang.
+func (c *Client) GetNewsRSS(ctx context.Context, query url.Values, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: "GET", path: "/news/feed.rss", query: query}, opts)
+}
+
+// GetNewsAtom calls GET /news/feed.atom: renders the published news as an Atom
+// feed. Query parameters: lang.
+func (c *Client) GetNewsAtom(ctx context.Context, query url.Values, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: "GET", path: "/news/feed.atom", query: query}, opts)
+}
+
+// FindNewsItemBySlug calls GET /news/by-slug/{slug}: finds news item by its
+// slug. Query parameters: lang.
+func (c *Client) FindNewsItemBySlug(ctx context.Context, slug string, query url.Values, opts ...RequestOption) (*NewsResponse, error) {
+	var out NewsResponse
+	if err := c.call(ctx, request{method: "GET", path: "/news/by-slug/" + url.PathEscape(slug), query: query}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FindNewsItem calls GET /news/{id}: finds news item from database with id and
+// creates a json response of the data. Query parameters: lang.
+func (c *Client) FindNewsItem(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*NewsResponse, error) {
+	var out NewsResponse
+	if err := c.call(ctx, request{method: "GET", path: "/news/" + url.PathEscape(id), query: query}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateNewsItem calls PUT /news/{id}: updates the content of a news item.
+// Requires an API key or token.
+func (c *Client) UpdateNewsItem(ctx context.Context, id string, body NewsRequest, opts ...RequestOption) (*NewsResponse, error) {
+	var out NewsResponse
+	if err := c.call(ctx, request{method: "PUT", path: "/news/" + url.PathEscape(id), body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNewsImage calls GET /news/{id}/image: serves the image of a news item.
+func (c *Client) GetNewsImage(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: "GET", path: "/news/" + url.PathEscape(id) + "/image"}, opts)
+}
+
+// SetNewsTags calls PUT /news/{id}/tags: replaces the tags of a news item.
+// Requires an API key or token.
+func (c *Client) SetNewsTags(ctx context.Context, id string, body NewsTermsRequest, opts ...RequestOption) ([]TermResponse, error) {
+	var out []TermResponse
+	err := c.call(ctx, request{method: "PUT", path: "/news/" + url.PathEscape(id) + "/tags", body: body}, &out, opts)
+	return out, err
+}
+
+// SetNewsCategories calls PUT /news/{id}/categories: replaces the categories of
+// a news item. Requires an API key or token.
+func (c *Client) SetNewsCategories(ctx context.Context, id string, body NewsTermsRequest, opts ...RequestOption) ([]TermResponse, error) {
+	var out []TermResponse
+	err := c.call(ctx, request{method: "PUT", path: "/news/" + url.PathEscape(id) + "/categories", body: body}, &out, opts)
+	return out, err
+}
+
+// LinkProjectToNews calls PUT /news/{id}/projects/{project_id}: links a project
+// to a news item. Requires an API key or token.
+func (c *Client) LinkProjectToNews(ctx context.Context, id string, projectID string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "PUT", path: "/news/" + url.PathEscape(id) + "/projects/" + url.PathEscape(projectID)}, nil, opts)
+}
+
+// UnlinkProjectFromNews calls DELETE /news/{id}/projects/{project_id}: removes
+// the link between a news item and a project. Requires an API key or token.
+func (c *Client) UnlinkProjectFromNews(ctx context.Context, id string, projectID string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/news/" + url.PathEscape(id) + "/projects/" + url.PathEscape(projectID)}, nil, opts)
+}
+
+// GetNewsTranslations calls GET /news/{id}/translations: lists the translations
+// of a news item.
+func (c *Client) GetNewsTranslations(ctx context.Context, id string, opts ...RequestOption) ([]TranslationResponse, error) {
+	var out []TranslationResponse
+	err := c.call(ctx, request{method: "GET", path: "/news/" + url.PathEscape(id) + "/translations"}, &out, opts)
+	return out, err
+}
+
+// PutNewsTranslation calls PUT /news/{id}/translations/{locale}: adds or
+// updates the translation of a news item. Requires an API key or token.
+func (c *Client) PutNewsTranslation(ctx context.Context, id string, locale string, body NewsTranslationRequest, opts ...RequestOption) (*TranslationResponse, error) {
+	var out TranslationResponse
+	if err := c.call(ctx, request{method: "PUT", path: "/news/" + url.PathEscape(id) + "/translations/" + url.PathEscape(locale), body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteNewsTranslation calls DELETE /news/{id}/translations/{locale}: deletes
+// the translation of a news item. Requires an API key or token.
+func (c *Client) DeleteNewsTranslation(ctx context.Context, id string, locale string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/news/" + url.PathEscape(id) + "/translations/" + url.PathEscape(locale)}, nil, opts)
+}
+
+// DeleteNewsItem calls DELETE /news/{id}: moves a news item to the trash and
+// creates a json response of the data. Requires an API key or token.
+func (c *Client) DeleteNewsItem(ctx context.Context, id string, opts ...RequestOption) (string, error) {
+	var out string
+	err := c.call(ctx, request{method: "DELETE", path: "/news/" + url.PathEscape(id)}, &out, opts)
+	return out, err
+}
+
+// GetNewsRevisions calls GET /news/{id}/revisions: lists the revisions of a
+// news item. Requires an API key or token.
+func (c *Client) GetNewsRevisions(ctx context.Context, id string, opts ...RequestOption) ([]RevisionResponse, error) {
+	var out []RevisionResponse
+	err := c.call(ctx, request{method: "GET", path: "/news/" + url.PathEscape(id) + "/revisions"}, &out, opts)
+	return out, err
+}
+
+// GetNewsRevisionDiff calls GET /news/{id}/revisions/diff: compares two
+// revisions of a news item. Query parameters: to, from. Requires an API key or
+// token.
+func (c *Client) GetNewsRevisionDiff(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*RevisionDiffResponse, error) {
+	var out RevisionDiffResponse
+	if err := c.call(ctx, request{method: "GET", path: "/news/" + url.PathEscape(id) + "/revisions/diff", query: query}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNewsRevision calls GET /news/{id}/revisions/{rev:[0-9]+}: returns a single
+// revision of a news item. Requires an API key or token.
+func (c *Client) GetNewsRevision(ctx context.Context, id string, rev string, opts ...RequestOption) (*RevisionResponse, error) {
+	var out RevisionResponse
+	if err := c.call(ctx, request{method: "GET", path: "/news/" + url.PathEscape(id) + "/revisions/" + url.PathEscape(rev)}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevertNewsItem calls POST /news/{id}/revisions/{rev:[0-9]+}/revert: restores
+// a news item to one of its revisions. Requires an API key or token.
+func (c *Client) RevertNewsItem(ctx context.Context, id string, rev string, opts ...RequestOption) (*RevisionResponse, error) {
+	var out RevisionResponse
+	if err := c.call(ctx, request{method: "POST", path: "/news/" + url.PathEscape(id) + "/revisions/" + url.PathEscape(rev) + "/revert"}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreNewsItem calls POST /news/{id}/restore: restores a deleted news item.
+// Requires an API key or token.
+func (c *Client) RestoreNewsItem(ctx context.Context, id string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "POST", path: "/news/" + url.PathEscape(id) + "/restore"}, nil, opts)
+}
+
+// PublishNewsItem calls POST /news/{id}/publish: publishes a news item,
+// immediately or at publish_at. Requires an API key or token.
+func (c *Client) PublishNewsItem(ctx context.Context, id string, body PublishRequest, opts ...RequestOption) (*NewsResponse, error) {
+	var out NewsResponse
+	if err := c.call(ctx, request{method: "POST", path: "/news/" + url.PathEscape(id) + "/publish", body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnpublishNewsItem calls POST /news/{id}/unpublish: moves a news item back to
+// draft. Requires an API key or token.
+func (c *Client) UnpublishNewsItem(ctx context.Context, id string, opts ...RequestOption) (*NewsResponse, error) {
+	var out NewsResponse
+	if err := c.call(ctx, request{method: "POST", path: "/news/" + url.PathEscape(id) + "/unpublish"}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ArchiveNewsItem calls POST /news/{id}/archive: archives a news item. Requires
+// an API key or token.
+func (c *Client) ArchiveNewsItem(ctx context.Context, id string, opts ...RequestOption) (*NewsResponse, error) {
+	var out NewsResponse
+	if err := c.call(ctx, request{method: "POST", path: "/news/" + url.PathEscape(id) + "/archive"}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTags calls GET /tag: lists all tags.
+func (c *Client) GetTags(ctx context.Context, opts ...RequestOption) ([]TermResponse, error) {
+	var out []TermResponse
+	err := c.call(ctx, request{method: "GET", path: "/tag"}, &out, opts)
+	return out, err
+}
+
+// AddTag calls POST /tag: adds a tag. Requires an API key or token.
+func (c *Client) AddTag(ctx context.Context, body TermRequest, opts ...RequestOption) (*TermResponse, error) {
+	var out TermResponse
+	if err := c.call(ctx, request{method: "POST", path: "/tag", body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTagCloud calls GET /tag/cloud: counts the published news items of every
+// tag.
+func (c *Client) GetTagCloud(ctx context.Context, opts ...RequestOption) ([]TermCountResponse, error) {
+	var out []TermCountResponse
+	err := c.call(ctx, request{method: "GET", path: "/tag/cloud"}, &out, opts)
+	return out, err
+}
+
+// DeleteTag calls DELETE /tag/{id}: deletes a tag and unlinks it from all news
+// items. Requires an API key or token.
+func (c *Client) DeleteTag(ctx context.Context, id string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/tag/" + url.PathEscape(id)}, nil, opts)
+}
+
+// GetCategories calls GET /category: lists all categories.
+func (c *Client) GetCategories(ctx context.Context, opts ...RequestOption) ([]TermResponse, error) {
+	var out []TermResponse
+	err := c.call(ctx, request{method: "GET", path: "/category"}, &out, opts)
+	return out, err
+}
+
+// AddCategory calls POST /category: adds a category. Requires an API key or
+// token.
+func (c *Client) AddCategory(ctx context.Context, body TermRequest, opts ...RequestOption) (*TermResponse, error) {
+	var out TermResponse
+	if err := c.call(ctx, request{method: "POST", path: "/category", body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCategoryCounts calls GET /category/counts: counts the published news items
+// of every category.
+func (c *Client) GetCategoryCounts(ctx context.Context, opts ...RequestOption) ([]TermCountResponse, error) {
+	var out []TermCountResponse
+	err := c.call(ctx, request{method: "GET", path: "/category/counts"}, &out, opts)
+	return out, err
+}
+
+// DeleteCategory calls DELETE /category/{id}: deletes a category and unlinks it
+// from all news items. Requires an API key or token.
+func (c *Client) DeleteCategory(ctx context.Context, id string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/category/" + url.PathEscape(id)}, nil, opts)
+}
+
+// GetClients calls GET /client: lists all clients.
+func (c *Client) GetClients(ctx context.Context, opts ...RequestOption) ([]ClientResponse, error) {
+	var out []ClientResponse
+	err := c.call(ctx, request{method: "GET", path: "/client"}, &out, opts)
+	return out, err
+}
+
+// GetTechnologies calls GET /technology: lists all technologies.
+func (c *Client) GetTechnologies(ctx context.Context, opts ...RequestOption) ([]TermResponse, error) {
+	var out []TermResponse
+	err := c.call(ctx, request{method: "GET", path: "/technology"}, &out, opts)
+	return out, err
+}
+
+// AddJobApplications calls POST /job: adds a new job to database and creates a
+// json response of the data.
+func (c *Client) AddJobApplications(ctx context.Context, body JobRequest, opts ...RequestOption) (*JobResponse, error) {
+	var out JobResponse
+	if err := c.call(ctx, request{method: "POST", path: "/job", body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetJobApplications calls GET /job: gets all job applications from database
+// with id and creates a json response of the data. Query parameters:
+// department, email, q, since, until. Requires an API key or token.
+func (c *Client) GetJobApplications(ctx context.Context, query url.Values, opts ...RequestOption) ([]JobResponse, error) {
+	var out []JobResponse
+	err := c.call(ctx, request{method: "GET", path: "/job", query: query}, &out, opts)
+	return out, err
+}
+
+// GetJobFormToken calls GET /job/form-token: issues a form token to be sent
+// back with the job application.
+func (c *Client) GetJobFormToken(ctx context.Context, opts ...RequestOption) (*FormTokenResponse, error) {
+	var out FormTokenResponse
+	if err := c.call(ctx, request{method: "GET", path: "/job/form-token"}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetJobRejections calls GET /job/rejections: lists rejected job applications
+// for review. Requires an API key or token.
+func (c *Client) GetJobRejections(ctx context.Context, opts ...RequestOption) ([]JobRejectionResponse, error) {
+	var out []JobRejectionResponse
+	err := c.call(ctx, request{method: "GET", path: "/job/rejections"}, &out, opts)
+	return out, err
+}
+
+// ExportJobApplications calls GET /job/export: streams the job applications
+// matching the list filters as a CSV or XLSX file. Query parameters: format,
+// columns, department, email, q, since, until. Requires an API key or token.
+func (c *Client) ExportJobApplications(ctx context.Context, query url.Values, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: "GET", path: "/job/export", query: query}, opts)
+}
+
+// FindJobApplicationByID calls GET /job/{id}: finds job applications from
+// database with id and creates a json response of the data. Requires an API key
+// or token.
+func (c *Client) FindJobApplicationByID(ctx context.Context, id string, opts ...RequestOption) (*JobResponse, error) {
+	var out JobResponse
+	if err := c.call(ctx, request{method: "GET", path: "/job/" + url.PathEscape(id)}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteJob calls DELETE /job/{id}: moves a job application to the trash and
+// creates a json response of the data. Requires an API key or token.
+func (c *Client) DeleteJob(ctx context.Context, id string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "DELETE", path: "/job/" + url.PathEscape(id)}, nil, opts)
+}
+
+// RestoreJob calls POST /job/{id}/restore: restores a deleted job application.
+// Requires an API key or token.
+func (c *Client) RestoreJob(ctx context.Context, id string, opts ...RequestOption) error {
+	return c.call(ctx, request{method: "POST", path: "/job/" + url.PathEscape(id) + "/restore"}, nil, opts)
+}
+
+// GetTrash calls GET /trash: lists the deleted items, most recently deleted
+// first. Query parameters: type. Requires an API key or token.
+func (c *Client) GetTrash(ctx context.Context, query url.Values, opts ...RequestOption) ([]TrashItemResponse, error) {
+	var out []TrashItemResponse
+	err := c.call(ctx, request{method: "GET", path: "/trash", query: query}, &out, opts)
+	return out, err
+}
+
+// GetAuditLog calls GET /audit: lists audit entries, newest first. Query
+// parameters: actor, action, resource_type, resource_id, request_id, since,
+// until, before_id, limit. Requires an API key or token.
+func (c *Client) GetAuditLog(ctx context.Context, query url.Values, opts ...RequestOption) ([]AuditResponse, error) {
+	var out []AuditResponse
+	err := c.call(ctx, request{method: "GET", path: "/audit", query: query}, &out, opts)
+	return out, err
+}
+
+// AddRelation calls POST /relation: adds relation to database and creates a
+// json response of the data.
+func (c *Client) AddRelation(ctx context.Context, body RelationRequest, opts ...RequestOption) (*RelationResponse, error) {
+	var out RelationResponse
+	if err := c.call(ctx, request{method: "POST", path: "/relation", body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BulkRelations calls POST /relation/bulk: runs create, update and delete
+// operations on relations in one transaction. Query parameters: mode. Requires
+// an API key or token.
+func (c *Client) BulkRelations(ctx context.Context, body []RelationBulkOperation, query url.Values, opts ...RequestOption) (*BulkResponse, error) {
+	var out BulkResponse
+	if err := c.call(ctx, request{method: "POST", path: "/relation/bulk", query: query, body: body}, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPISpec calls GET /openapi.json: serves the OpenAPI document generated
+// from the routes and annotated types.
+func (c *Client) GetOpenAPISpec(ctx context.Context, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: "GET", path: "/openapi.json"}, opts)
+}
+
+// GetAPIDocs calls GET /docs: serves the interactive API documentation.
+func (c *Client) GetAPIDocs(ctx context.Context, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.stream(ctx, request{method: "GET", path: "/docs"}, opts)
+}
+
+// HealthCheck calls GET /health: checks application status.
+func (c *Client) HealthCheck(ctx context.Context, opts ...RequestOption) (map[string]string, error) {
+	var out map[string]string
+	err := c.call(ctx, request{method: "GET", path: "/health"}, &out, opts)
+	return out, err
+}
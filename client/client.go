@@ -0,0 +1,305 @@
+// Package client calls the Cerci service from Go. The request and response
+// types and a method for every route are generated from the service with
+// go generate, see cmd/openapi, so they always match the server.
+//
+//	c := client.New("https://cerci.example.com", client.WithAPIKey(key))
+//	news, err := c.FindNewsItem(ctx, "42", nil)
+//	if client.IsStatus(err, http.StatusNotFound) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retry defaults of New
+const (
+	DefaultMaxRetries = 2
+	DefaultRetryWait  = 200 * time.Millisecond
+)
+
+// maxErrorBody limits the error body read into Error
+const maxErrorBody = 1 << 20
+
+// Client calls the routes of the service. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	token      string
+	maxRetries int
+	retryWait  time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with httpClient instead of http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey authenticates the requests with an api key, sent as X-API-Key
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithBearerToken authenticates the requests with a bearer token
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithRetries sets how many times idempotent calls are retried after a
+// network error or a 429, 502, 503 or 504 response, and the wait before the
+// first retry. The wait doubles with every retry unless the server sends
+// Retry-After.
+func WithRetries(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries, c.retryWait = maxRetries, wait
+	}
+}
+
+// New returns a client of the service at baseURL
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		maxRetries: DefaultMaxRetries,
+		retryWait:  DefaultRetryWait,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// RequestOption changes a single call
+type RequestOption func(*callOptions)
+
+type callOptions struct {
+	header   http.Header
+	response *http.Header
+}
+
+// WithHeader sets a request header of the call
+func WithHeader(key string, value string) RequestOption {
+	return func(o *callOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// IfMatch sends the ETag of the version an update or delete applies to
+func IfMatch(etag string) RequestOption {
+	return WithHeader("If-Match", etag)
+}
+
+// IfNoneMatch sends the ETag of a cached item, the call fails with a 304
+// Error when the item did not change
+func IfNoneMatch(etag string) RequestOption {
+	return WithHeader("If-None-Match", etag)
+}
+
+// AcceptLanguage asks for the translation of an item in locale
+func AcceptLanguage(locale string) RequestOption {
+	return WithHeader("Accept-Language", locale)
+}
+
+// ResponseHeader stores the response header of the call in header, for
+// example to read the ETag of an item
+func ResponseHeader(header *http.Header) RequestOption {
+	return func(o *callOptions) {
+		o.response = header
+	}
+}
+
+// Error is returned for responses with a status of 300 or more
+type Error struct {
+	StatusCode int
+	// Message is the message of the error response
+	Message string
+	// RequestID identifies the request in the logs of the service
+	RequestID string
+	// Issues lists the mismatches when the request did not match the API spec
+	Issues []ValidationIssue
+	// Body is the raw error response
+	Body []byte
+}
+
+func (e *Error) Error() string {
+	text := fmt.Sprintf("cerci: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		text += ": " + e.Message
+	}
+	for _, issue := range e.Issues {
+		text += fmt.Sprintf("; %s %s%s %s", issue.In, issue.Name, issue.Pointer, issue.Message)
+	}
+	return text
+}
+
+// IsStatus reports whether err is an Error with the given status
+func IsStatus(err error, status int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == status
+}
+
+// request is a single call of a route
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+}
+
+// call sends r and decodes the JSON response into out, when out is not nil
+func (c *Client) call(ctx context.Context, r request, out interface{}, opts []RequestOption) error {
+	resp, err := c.send(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("cerci: failed to decode %s %s response: %v", r.method, r.path, err)
+	}
+	return nil
+}
+
+// stream sends r and returns the response body, which the caller must close
+func (c *Client) stream(ctx context.Context, r request, opts []RequestOption) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, r, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// send sends r, retrying idempotent calls, and turns error statuses into Error
+func (c *Client) send(ctx context.Context, r request, opts []RequestOption) (*http.Response, error) {
+	o := callOptions{header: http.Header{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return nil, fmt.Errorf("cerci: failed to encode %s %s request: %v", r.method, r.path, err)
+		}
+	}
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, r.method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.apiKey != "" {
+			req.Header.Set("X-API-Key", c.apiKey)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		for key, values := range o.header {
+			req.Header[key] = values
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if attempt < c.maxRetries && idempotent(r.method) && ctx.Err() == nil && (err != nil || retryable(resp.StatusCode)) {
+			wait := c.retryWait << uint(attempt)
+			if resp != nil {
+				if after := retryAfter(resp); after > 0 {
+					wait = after
+				}
+				io.Copy(ioutil.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if o.response != nil {
+			*o.response = resp.Header
+		}
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			defer resp.Body.Close()
+			return nil, decodeError(resp)
+		}
+		return resp, nil
+	}
+}
+
+// decodeError reads the error response of the service
+func decodeError(resp *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	e := &Error{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID"), Body: body}
+	var decoded struct {
+		Message string            `json:"message"`
+		Errors  []ValidationIssue `json:"errors"`
+	}
+	if json.Unmarshal(body, &decoded) == nil {
+		e.Message, e.Issues = decoded.Message, decoded.Errors
+	}
+	return e
+}
+
+// idempotent reports whether a call may be sent again after a failure
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the wait a response asks for in seconds in Retry-After
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
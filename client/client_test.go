@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer answers the requests it receives with the given responses in
+// order, repeating the last one, and records what it received
+type testServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+type testResponse struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+func newTestServer(t *testing.T, responses ...testResponse) *testServer {
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.bodies = append(s.bodies, string(body))
+		count := len(s.requests)
+		s.mu.Unlock()
+
+		response := responses[len(responses)-1]
+		if count <= len(responses) {
+			response = responses[count-1]
+		}
+		for key, value := range response.header {
+			writer.Header().Set(key, value)
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(response.status)
+		writer.Write([]byte(response.body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func TestRetries(t *testing.T) {
+	unavailable := testResponse{status: http.StatusServiceUnavailable, body: `{"status":503,"message":"Try again"}`}
+	ok := testResponse{status: http.StatusOK, body: `{"id":42,"title":"Hello"}`}
+	tests := []struct {
+		name      string
+		responses []testResponse
+		call      func(c *Client) (*NewsResponse, error)
+		requests  int
+		status    int
+	}{
+		{"get retried until ok", []testResponse{unavailable, {status: http.StatusBadGateway}, ok},
+			func(c *Client) (*NewsResponse, error) { return c.FindNewsItem(context.Background(), "42", nil) }, 3, 0},
+		{"put retried", []testResponse{{status: http.StatusTooManyRequests}, ok},
+			func(c *Client) (*NewsResponse, error) {
+				return c.UpdateNewsItem(context.Background(), "42", NewsRequest{Title: "Hello"})
+			}, 2, 0},
+		{"get gives up", []testResponse{unavailable},
+			func(c *Client) (*NewsResponse, error) { return c.FindNewsItem(context.Background(), "42", nil) }, 3, http.StatusServiceUnavailable},
+		{"post not retried", []testResponse{unavailable, ok},
+			func(c *Client) (*NewsResponse, error) {
+				return c.AddNewsItem(context.Background(), NewsRequest{Title: "Hello"})
+			}, 1, http.StatusServiceUnavailable},
+		{"client error not retried", []testResponse{{status: http.StatusNotFound, body: `{"message":"News item [42] not found"}`}, ok},
+			func(c *Client) (*NewsResponse, error) { return c.FindNewsItem(context.Background(), "42", nil) }, 1, http.StatusNotFound},
+		{"internal error not retried", []testResponse{{status: http.StatusInternalServerError}, ok},
+			func(c *Client) (*NewsResponse, error) { return c.FindNewsItem(context.Background(), "42", nil) }, 1, http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t, test.responses...)
+			c := New(server.URL, WithRetries(2, time.Millisecond), WithAPIKey("key"))
+			news, err := test.call(c)
+
+			if got := server.count(); got != test.requests {
+				t.Errorf("server received %d requests, want %d", got, test.requests)
+			}
+			if test.status != 0 {
+				if !IsStatus(err, test.status) {
+					t.Errorf("got %v, want a %d Error", err, test.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if news.ID != 42 || news.Title != "Hello" {
+				t.Errorf("got %+v", news)
+			}
+			for i, req := range server.requests {
+				if req.Header.Get("X-API-Key") != "key" {
+					t.Errorf("request %d has no api key", i)
+				}
+				if server.bodies[i] != server.bodies[0] {
+					t.Errorf("request %d sent %q, the first sent %q", i, server.bodies[i], server.bodies[0])
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	server := newTestServer(t, testResponse{status: http.StatusServiceUnavailable, header: map[string]string{"Retry-After": "30"}},
+		testResponse{status: http.StatusOK, body: `{}`})
+	c := New(server.URL, WithRetries(2, time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.FindNewsItem(ctx, "42", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want the context deadline while waiting for Retry-After", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %s after the context ended", elapsed)
+	}
+	if got := server.count(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"0", 0},
+		{"-1", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+	for _, test := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", test.value)
+		if got := retryAfter(resp); got != test.want {
+			t.Errorf("retryAfter(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		response testResponse
+		want     Error
+		text     string
+	}{
+		{"validation issues", testResponse{status: http.StatusBadRequest, header: map[string]string{"X-Request-ID": "req-1"},
+			body: `{"status":400,"message":"Request does not match the API spec","errors":[` +
+				`{"in":"path","name":"id","message":"must be an integer"},{"in":"body","pointer":"/title","message":"is required"}]}`},
+			Error{StatusCode: http.StatusBadRequest, Message: "Request does not match the API spec", RequestID: "req-1",
+				Issues: []ValidationIssue{{In: "path", Name: "id", Message: "must be an integer"}, {In: "body", Pointer: "/title", Message: "is required"}}},
+			"cerci: 400 Bad Request: Request does not match the API spec; path id must be an integer; body /title is required"},
+		{"error response", testResponse{status: http.StatusNotFound, body: `{"status":404,"error":{},"message":"News item [7] not found"}`},
+			Error{StatusCode: http.StatusNotFound, Message: "News item [7] not found"},
+			"cerci: 404 Not Found: News item [7] not found"},
+		{"not json", testResponse{status: http.StatusBadGateway, body: `<html>bad gateway</html>`},
+			Error{StatusCode: http.StatusBadGateway},
+			"cerci: 502 Bad Gateway"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t, test.response)
+			c := New(server.URL, WithRetries(0, 0))
+			_, err := c.FindNewsItem(context.Background(), "7", nil)
+
+			var got *Error
+			if !errors.As(err, &got) {
+				t.Fatalf("got %v, want an Error", err)
+			}
+			if string(got.Body) != test.response.body {
+				t.Errorf("Body %q, want %q", got.Body, test.response.body)
+			}
+			got.Body = nil
+			if !reflect.DeepEqual(*got, test.want) {
+				t.Errorf("got  %+v\nwant %+v", *got, test.want)
+			}
+			if got.Error() != test.text {
+				t.Errorf("Error() %q, want %q", got.Error(), test.text)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Roles of api keys, see auth.go of the service
+var apiKeyRoles = map[string]bool{"editor": true, "admin": true}
+
+// hashAPIKey returns the stored hash of key, it must match HashAPIKey of the service
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// createAPIKey stores the hash of a new random key and prints the key, which
+// cannot be shown again
+func createAPIKey(c *cli, args []string) error {
+	fs := c.flags()
+	name := fs.String("name", "", "name of the key, recorded as the actor of audit log entries")
+	role := fs.String("role", "editor", "editor or admin")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("apikey create needs -name")
+	}
+	if !apiKeyRoles[*role] {
+		return fmt.Errorf("unknown role %q, use editor or admin", *role)
+	}
+	db, err := c.database()
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return err
+	}
+	key := hex.EncodeToString(buf)
+	sql := "INSERT INTO api_key(name,role,key_hash,created) VALUES($1,$2,$3,$4)"
+	if _, err = db.ExecContext(c.ctx, sql, *name, *role, hashAPIKey(key), time.Now()); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, key)
+	return nil
+}
+
+func listAPIKeys(c *cli, args []string) error {
+	fs := c.flags()
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	db, err := c.database()
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(c.ctx, "SELECT name,role,created,revoked_at FROM api_key ORDER BY name")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var table [][]string
+	for rows.Next() {
+		var name, role string
+		var created time.Time
+		var revoked *time.Time
+		if err = rows.Scan(&name, &role, &created, &revoked); err != nil {
+			return err
+		}
+		revokedAt := ""
+		if revoked != nil {
+			revokedAt = revoked.Format(time.RFC3339)
+		}
+		table = append(table, []string{name, role, created.Format(time.RFC3339), revokedAt})
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	c.table([]string{"NAME", "ROLE", "CREATED", "REVOKED"}, table)
+	return nil
+}
+
+func revokeAPIKey(c *cli, args []string) error {
+	fs := c.flags()
+	name := fs.String("name", "", "name of the key")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("apikey revoke needs -name")
+	}
+	db, err := c.database()
+	if err != nil {
+		return err
+	}
+	result, err := db.ExecContext(c.ctx, "UPDATE api_key SET revoked_at=$1 WHERE name=$2 AND revoked_at IS NULL", time.Now(), *name)
+	if err != nil {
+		return err
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return fmt.Errorf("no active api key named %q", *name)
+	}
+	fmt.Fprintf(c.out, "revoked %s\n", *name)
+	return nil
+}
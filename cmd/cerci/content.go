@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codonex/cerci-service/client"
+)
+
+// queryFlags collects key=value flags into query parameters
+type queryFlags url.Values
+
+func (q queryFlags) String() string {
+	return url.Values(q).Encode()
+}
+
+func (q queryFlags) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("%q is not key=value", value)
+	}
+	url.Values(q).Add(value[:i], value[i+1:])
+	return nil
+}
+
+// listFlags adds the flags shared by the list commands
+func listFlags(fs *flag.FlagSet) (url.Values, *bool) {
+	query := url.Values{}
+	fs.Var(queryFlags(query), "filter", "query parameter as key=value, may be repeated")
+	asJSON := fs.Bool("json", false, "print the response as JSON")
+	return query, asJSON
+}
+
+// setQuery sets the query parameter name unless value is empty
+func setQuery(query url.Values, name string, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+// readJSON decodes the JSON file at path, - reads stdin
+func readJSON(path string, v interface{}) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// printJSON writes v as indented JSON
+func (c *cli) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// ifMatch returns the If-Match option of a delete. Without a version the
+// current ETag is read with find first.
+func ifMatch(version int, find func(opts ...client.RequestOption) error) (client.RequestOption, error) {
+	if version > 0 {
+		return client.IfMatch(strconv.Quote(strconv.Itoa(version))), nil
+	}
+	var header http.Header
+	if err := find(client.ResponseHeader(&header)); err != nil {
+		return nil, err
+	}
+	return client.IfMatch(header.Get("ETag")), nil
+}
+
+func listNews(c *cli, args []string) error {
+	fs := c.flags()
+	query, asJSON := listFlags(fs)
+	status := fs.String("status", "", "draft, published, archived or all, editors only")
+	lang := fs.String("lang", "", "language of the translations")
+	tag := fs.String("tag", "", "tag slug")
+	category := fs.String("category", "", "category slug")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	setQuery(query, "status", *status)
+	setQuery(query, "lang", *lang)
+	setQuery(query, "tag", *tag)
+	setQuery(query, "category", *category)
+
+	news, err := c.client().GetNewsItems(c.ctx, query)
+	if err != nil || *asJSON {
+		if err == nil {
+			err = c.printJSON(news)
+		}
+		return err
+	}
+	var rows [][]string
+	for _, item := range news {
+		rows = append(rows, []string{strconv.Itoa(item.ID), item.Status, strconv.Itoa(item.Version),
+			item.Created.Format("2006-01-02"), item.Slug, item.Title})
+	}
+	c.table([]string{"ID", "STATUS", "VERSION", "CREATED", "SLUG", "TITLE"}, rows)
+	return nil
+}
+
+func createNews(c *cli, args []string) error {
+	fs := c.flags()
+	file := fs.String("f", "", "JSON file of the news request, - for stdin")
+	title := fs.String("title", "", "title, when -f is not given")
+	detail := fs.String("detail", "", "detail, when -f is not given")
+	format := fs.String("format", "", "detail format: text, markdown or html")
+	status := fs.String("status", "", "draft or published")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	var request client.NewsRequest
+	if *file != "" {
+		if err := readJSON(*file, &request); err != nil {
+			return err
+		}
+	}
+	if *title != "" {
+		request.Title = *title
+	}
+	if *detail != "" {
+		request.Detail = *detail
+	}
+	if *format != "" {
+		request.DetailFormat = *format
+	}
+	if *status != "" {
+		request.Status = *status
+	}
+	if request.Title == "" || request.Detail == "" {
+		return errors.New("news create needs a title and a detail, use -f or -title and -detail")
+	}
+
+	news, err := c.client().AddNewsItem(c.ctx, request)
+	if err != nil {
+		return err
+	}
+	return c.printJSON(news)
+}
+
+func deleteNews(c *cli, args []string) error {
+	fs := c.flags()
+	id := fs.String("id", "", "id of the news item")
+	version := fs.Int("version", 0, "version the delete applies to, the current version when 0")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("news delete needs -id")
+	}
+	match, err := ifMatch(*version, func(opts ...client.RequestOption) error {
+		_, err := c.client().FindNewsItem(c.ctx, *id, nil, opts...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	message, err := c.client().DeleteNewsItem(c.ctx, *id, match)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, message)
+	return nil
+}
+
+func listProjects(c *cli, args []string) error {
+	fs := c.flags()
+	query, asJSON := listFlags(fs)
+	status := fs.String("status", "", "planned, ongoing or completed")
+	lang := fs.String("lang", "", "language of the translations")
+	clientName := fs.String("client", "", "client slug")
+	technology := fs.String("technology", "", "technology slug")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	setQuery(query, "status", *status)
+	setQuery(query, "lang", *lang)
+	setQuery(query, "client", *clientName)
+	setQuery(query, "technology", *technology)
+
+	projects, err := c.client().GetProjectItems(c.ctx, query)
+	if err != nil || *asJSON {
+		if err == nil {
+			err = c.printJSON(projects)
+		}
+		return err
+	}
+	var rows [][]string
+	for _, project := range projects {
+		rows = append(rows, []string{strconv.Itoa(project.ID), project.Status, strconv.Itoa(project.Version),
+			project.StartDate.Format("2006-01-02"), project.Slug, project.ProjectName})
+	}
+	c.table([]string{"ID", "STATUS", "VERSION", "START", "SLUG", "NAME"}, rows)
+	return nil
+}
+
+func createProject(c *cli, args []string) error {
+	fs := c.flags()
+	file := fs.String("f", "", "JSON file of the project request, - for stdin")
+	name := fs.String("name", "", "project name, when -f is not given")
+	detail := fs.String("detail", "", "detail, when -f is not given")
+	start := fs.String("start", "", "start date, 2006-01-02")
+	finish := fs.String("finish", "", "finish date, 2006-01-02")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	var request client.ProjectRequest
+	if *file != "" {
+		if err := readJSON(*file, &request); err != nil {
+			return err
+		}
+	}
+	if *name != "" {
+		request.ProjectName = *name
+	}
+	if *detail != "" {
+		request.Detail = *detail
+	}
+	for _, date := range []struct {
+		value  string
+		target *time.Time
+	}{{*start, &request.StartDate}, {*finish, &request.FinishDate}} {
+		if date.value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", date.value)
+		if err != nil {
+			return err
+		}
+		*date.target = parsed
+	}
+	if request.ProjectName == "" || request.Detail == "" {
+		return errors.New("project create needs a name and a detail, use -f or -name and -detail")
+	}
+
+	project, err := c.client().AddProjectItem(c.ctx, request)
+	if err != nil {
+		return err
+	}
+	return c.printJSON(project)
+}
+
+func deleteProject(c *cli, args []string) error {
+	fs := c.flags()
+	id := fs.String("id", "", "id of the project")
+	version := fs.Int("version", 0, "version the delete applies to, the current version when 0")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("project delete needs -id")
+	}
+	match, err := ifMatch(*version, func(opts ...client.RequestOption) error {
+		_, err := c.client().FindProjectItem(c.ctx, *id, nil, opts...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err = c.client().DeleteProjectItem(c.ctx, *id, match); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "Deleted project %s\n", *id)
+	return nil
+}
+
+// health prints the health of the service, it fails unless the service is up
+func health(c *cli, args []string) error {
+	fs := c.flags()
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	status, err := c.client().HealthCheck(c.ctx)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, key := range sortedKeys(status) {
+		rows = append(rows, []string{key, status[key]})
+	}
+	c.table([]string{"CHECK", "STATUS"}, rows)
+	if status["status"] != "up" {
+		return errors.New("service is down")
+	}
+	return nil
+}
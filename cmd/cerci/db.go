@@ -0,0 +1,84 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	_ "github.com/lib/pq" //import postgres driver
+	"gopkg.in/yaml.v2"
+)
+
+// serviceConfig is the part of the service config the commands need
+type serviceConfig struct {
+	Database struct {
+		User         string `yaml:"user" envconfig:"DB_USER"`
+		Password     string `yaml:"password" envconfig:"DB_PASSWORD"`
+		PasswordFile string `yaml:"password_file" envconfig:"DB_PASSWORD_FILE"`
+		Name         string `yaml:"name" envconfig:"DB_NAME"`
+	} `yaml:"database"`
+}
+
+// readServiceConfig reads the service config like NewConfig of the service
+// does: the file, the overlay of APP_ENV, the environment overrides and the
+// password file. Both are tested against testdata/config.yaml.
+func readServiceConfig(path string) (*serviceConfig, error) {
+	conf := &serviceConfig{}
+	paths := []string{path}
+	if env := os.Getenv("APP_ENV"); env != "" {
+		ext := filepath.Ext(path)
+		paths = append(paths, strings.TrimSuffix(path, ext)+"."+env+ext)
+	}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = yaml.Unmarshal(data, conf); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	if err := envconfig.Process("", &conf.Database); err != nil {
+		return nil, err
+	}
+	if conf.Database.PasswordFile != "" {
+		data, err := ioutil.ReadFile(conf.Database.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("database.password_file: %v", err)
+		}
+		conf.Database.Password = strings.TrimRight(string(data), "\r\n")
+	}
+	return conf, nil
+}
+
+// database returns the connection of the run, from -dsn or else from the
+// database section of the service config
+func (c *cli) database() (*dbsql.DB, error) {
+	if c.db != nil {
+		return c.db, nil
+	}
+	dsn := c.dsn
+	if dsn == "" {
+		conf, err := readServiceConfig(c.config)
+		if err != nil {
+			return nil, fmt.Errorf("no -dsn given and %v", err)
+		}
+		dsn = fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable",
+			conf.Database.User, conf.Database.Password, conf.Database.Name)
+	}
+
+	db, err := dbsql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.PingContext(c.ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	c.db = db
+	return db, nil
+}
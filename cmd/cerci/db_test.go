@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestReadServiceConfig reads the fixture the config tests of the service
+// read, so both loaders resolve the database settings alike
+func TestReadServiceConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	passwordFile := filepath.Join(dir, "db_password")
+	if err = ioutil.WriteFile(passwordFile, []byte("secret_password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantUser     string
+		wantPassword string
+		wantName     string
+	}{
+		{"file only", nil, "file_user", "file_password", "file_db"},
+		{"overlay over file", map[string]string{"APP_ENV": "staging"}, "file_user", "file_password", "staging_db"},
+		{"env over overlay", map[string]string{"APP_ENV": "staging", "DB_NAME": "env_db", "DB_USER": "env_user"},
+			"env_user", "file_password", "env_db"},
+		{"env password", map[string]string{"DB_PASSWORD": "env_password"}, "file_user", "env_password", "file_db"},
+		{"password file over env", map[string]string{"APP_ENV": "staging", "DB_PASSWORD": "env_password", "DB_PASSWORD_FILE": passwordFile},
+			"file_user", "secret_password", "staging_db"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, key := range []string{"APP_ENV", "DB_USER", "DB_PASSWORD", "DB_PASSWORD_FILE", "DB_NAME"} {
+				previous, ok := os.LookupEnv(key)
+				os.Unsetenv(key)
+				if value := test.env[key]; value != "" {
+					os.Setenv(key, value)
+				}
+				key := key
+				t.Cleanup(func() {
+					if ok {
+						os.Setenv(key, previous)
+					} else {
+						os.Unsetenv(key)
+					}
+				})
+			}
+
+			conf, err := readServiceConfig("../../testdata/config.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			db := conf.Database
+			if db.User != test.wantUser || db.Password != test.wantPassword || db.Name != test.wantName {
+				t.Errorf("got user %q password %q name %q, want %q %q %q", db.User, db.Password, db.Name,
+					test.wantUser, test.wantPassword, test.wantName)
+			}
+		})
+	}
+}
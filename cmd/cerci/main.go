@@ -0,0 +1,232 @@
+// Command cerci runs operational tasks against the Cerci service. Content and
+// health commands call the API with the client package, migrations, api keys
+// and relation exports work on the database directly.
+//
+//	cerci [global flags] <command> <subcommand> [flags]
+//	cerci -url https://api.codonex.com -api-key $KEY news list -status draft
+//	cerci -config /etc/cerci/config.yaml migrate up
+//
+// Run cerci help for the list of commands.
+package main
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/codonex/cerci-service/client"
+)
+
+// command is a node of the command tree, either a group of subcommands or a
+// leaf that runs
+type command struct {
+	name     string
+	short    string
+	commands []*command
+	run      func(c *cli, args []string) error
+}
+
+var root = &command{
+	name: "cerci",
+	commands: []*command{
+		{name: "news", short: "List, create and delete news items", commands: []*command{
+			{name: "list", short: "List news items", run: listNews},
+			{name: "create", short: "Create a news item", run: createNews},
+			{name: "delete", short: "Move a news item to the trash", run: deleteNews},
+		}},
+		{name: "project", short: "List, create and delete projects", commands: []*command{
+			{name: "list", short: "List projects", run: listProjects},
+			{name: "create", short: "Create a project", run: createProject},
+			{name: "delete", short: "Move a project to the trash", run: deleteProject},
+		}},
+		{name: "relation", short: "Import and export relation trees", commands: []*command{
+			{name: "import", short: "Create the relations of a tree file through the API", run: importRelations},
+			{name: "export", short: "Write the relation tree from the database", run: exportRelations},
+		}},
+		{name: "migrate", short: "Run database migrations", commands: []*command{
+			{name: "up", short: "Apply the pending migrations", run: migrateUp},
+			{name: "status", short: "List migrations and whether they are applied", run: migrateStatus},
+			{name: "mark", short: "Record migrations as applied without running them", run: migrateMark},
+		}},
+		{name: "apikey", short: "Manage the api keys stored in the database", commands: []*command{
+			{name: "create", short: "Create an api key and print it once", run: createAPIKey},
+			{name: "list", short: "List api keys", run: listAPIKeys},
+			{name: "revoke", short: "Revoke an api key", run: revokeAPIKey},
+		}},
+		{name: "health", short: "Check the health of the service", run: health},
+	},
+}
+
+// cli holds the global flags and the connections of a run
+type cli struct {
+	ctx    context.Context
+	out    io.Writer
+	url    string
+	apiKey string
+	config string
+	dsn    string
+	path   string
+
+	api *client.Client
+	db  *dbsql.DB
+}
+
+// errUsage is returned when the command line is wrong, usage was printed
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, stop := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		stop()
+	}()
+
+	c := &cli{ctx: ctx, out: os.Stdout}
+	err := c.main(os.Args[1:])
+	if c.db != nil {
+		c.db.Close()
+	}
+	stop()
+	switch {
+	case err == errUsage:
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "cerci:", err)
+		os.Exit(1)
+	}
+}
+
+// main parses the global flags and runs the command of args
+func (c *cli) main(args []string) error {
+	c.path = root.name
+	fs := flag.NewFlagSet(root.name, flag.ContinueOnError)
+	fs.StringVar(&c.url, "url", envOr("CERCI_URL", "http://localhost:8080"), "base url of the API, $CERCI_URL")
+	fs.StringVar(&c.apiKey, "api-key", os.Getenv("CERCI_API_KEY"), "api key of the API, $CERCI_API_KEY")
+	fs.StringVar(&c.config, "config", envOr("CERCI_CONFIG", "./config.yaml"), "service config file with the database settings, $CERCI_CONFIG")
+	fs.StringVar(&c.dsn, "dsn", os.Getenv("CERCI_DSN"), "postgres connection string, overrides -config, $CERCI_DSN")
+	fs.Usage = func() { c.usage(fs.Output(), root, fs) }
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return errUsage
+	}
+
+	cmd := root
+	args = fs.Args()
+	for cmd.run == nil {
+		if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			if cmd == root {
+				c.usage(os.Stderr, cmd, fs)
+			} else {
+				c.usage(os.Stderr, cmd, nil)
+			}
+			if len(args) == 0 {
+				return errUsage
+			}
+			return nil
+		}
+		next := cmd.find(args[0])
+		if next == nil {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", strings.TrimSpace(c.path+" "+args[0]))
+			c.usage(os.Stderr, cmd, nil)
+			return errUsage
+		}
+		cmd, args, c.path = next, args[1:], c.path+" "+next.name
+	}
+	return cmd.run(c, args)
+}
+
+func (cmd *command) find(name string) *command {
+	for _, sub := range cmd.commands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// usage prints the subcommands of cmd, and the global flags for the root
+func (c *cli) usage(out io.Writer, cmd *command, global *flag.FlagSet) {
+	if cmd == root {
+		fmt.Fprintf(out, "Usage: cerci [global flags] <command> <subcommand> [flags]\n\nCommands:\n")
+	} else {
+		fmt.Fprintf(out, "Usage: %s <subcommand> [flags]\n\nCommands:\n", c.path)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, sub := range cmd.commands {
+		fmt.Fprintf(w, "  %s\t%s\n", sub.name, sub.short)
+	}
+	w.Flush()
+	if global != nil {
+		fmt.Fprintf(out, "\nGlobal flags:\n")
+		global.PrintDefaults()
+	}
+}
+
+// flags returns the flag set of the running command
+func (c *cli) flags() *flag.FlagSet {
+	return flag.NewFlagSet(c.path, flag.ContinueOnError)
+}
+
+// parse parses the flags of the running command, it rejects extra arguments
+func (c *cli) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "%s: unexpected arguments %v\n", c.path, fs.Args())
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// client returns the API client of the run
+func (c *cli) client() *client.Client {
+	if c.api == nil {
+		var options []client.Option
+		if c.apiKey != "" {
+			options = append(options, client.WithAPIKey(c.apiKey))
+		}
+		c.api = client.New(c.url, options...)
+	}
+	return c.api
+}
+
+// table writes rows aligned in columns
+func (c *cli) table(header []string, rows [][]string) {
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+func envOr(name string, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
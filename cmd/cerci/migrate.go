@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationLock is the advisory lock held while migrations run, so that two
+// deployments do not migrate at once
+const migrationLock = 7261001
+
+// migrationFile matches migration file names, 014_api_keys.sql
+var migrationFile = regexp.MustCompile(`^([0-9]+)_(.+)\.sql$`)
+
+type migration struct {
+	number  int
+	version string
+	name    string
+	path    string
+	applied *time.Time
+}
+
+// migrationFlags adds the flags shared by the migrate commands
+func migrationFlags(fs *flag.FlagSet) *string {
+	return fs.String("dir", "db/migrations", "directory of the migration files")
+}
+
+// migrations returns the migrations of dir in order, with the time they were
+// applied at
+func (c *cli) migrations(db *dbsql.Conn, dir string) ([]migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []migration
+	for _, file := range files {
+		if groups := migrationFile.FindStringSubmatch(file.Name()); groups != nil && !file.IsDir() {
+			number, _ := strconv.Atoi(groups[1])
+			migrations = append(migrations, migration{number: number, version: groups[1], name: groups[2],
+				path: filepath.Join(dir, file.Name())})
+		}
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].number < migrations[j].number })
+
+	sql := "CREATE TABLE IF NOT EXISTS schema_migrations(version character varying(32) NOT NULL PRIMARY KEY, name text NOT NULL, applied_at timestamp NOT NULL)"
+	if _, err = db.ExecContext(c.ctx, sql); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(c.ctx, "SELECT version,applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[string]time.Time{}
+	for rows.Next() {
+		var version string
+		var at time.Time
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	for i := range migrations {
+		if at, ok := applied[migrations[i].version]; ok {
+			migrations[i].applied = &at
+		}
+	}
+	return migrations, rows.Err()
+}
+
+// withMigrationLock runs f on a connection holding the migration lock
+func (c *cli) withMigrationLock(f func(conn *dbsql.Conn) error) error {
+	db, err := c.database()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err = conn.ExecContext(c.ctx, "SELECT pg_advisory_lock($1)", migrationLock); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLock)
+	return f(conn)
+}
+
+// migrateUp applies the pending migrations in order, each in its own transaction
+func migrateUp(c *cli, args []string) error {
+	fs := c.flags()
+	dir := migrationFlags(fs)
+	to := fs.Int("to", 0, "last version to apply, all when 0")
+	dryRun := fs.Bool("dry-run", false, "only list the migrations that would run")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	return c.withMigrationLock(func(conn *dbsql.Conn) error {
+		migrations, err := c.migrations(conn, *dir)
+		if err != nil {
+			return err
+		}
+		count := 0
+		for _, m := range migrations {
+			if m.applied != nil || (*to > 0 && m.number > *to) {
+				continue
+			}
+			count++
+			if *dryRun {
+				fmt.Fprintf(c.out, "pending %s_%s\n", m.version, m.name)
+				continue
+			}
+			if err = c.applyMigration(conn, m); err != nil {
+				return fmt.Errorf("migration %s_%s failed: %v", m.version, m.name, err)
+			}
+			fmt.Fprintf(c.out, "applied %s_%s\n", m.version, m.name)
+		}
+		if count == 0 {
+			fmt.Fprintln(c.out, "no pending migrations")
+		}
+		return nil
+	})
+}
+
+func (c *cli) applyMigration(conn *dbsql.Conn, m migration) error {
+	script, err := ioutil.ReadFile(m.path)
+	if err != nil {
+		return err
+	}
+	tx, err := conn.BeginTx(c.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err = tx.ExecContext(c.ctx, string(script)); err != nil {
+		return err
+	}
+	sql := "INSERT INTO schema_migrations(version,name,applied_at) VALUES($1,$2,$3)"
+	if _, err = tx.ExecContext(c.ctx, sql, m.version, m.name, time.Now()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// migrateStatus lists the migrations and when they were applied
+func migrateStatus(c *cli, args []string) error {
+	fs := c.flags()
+	dir := migrationFlags(fs)
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	return c.withMigrationLock(func(conn *dbsql.Conn) error {
+		migrations, err := c.migrations(conn, *dir)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, m := range migrations {
+			applied := "pending"
+			if m.applied != nil {
+				applied = m.applied.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{m.version, m.name, applied})
+		}
+		c.table([]string{"VERSION", "NAME", "APPLIED"}, rows)
+		return nil
+	})
+}
+
+// migrateMark records migrations as applied without running them, for
+// databases that were migrated by hand before schema_migrations existed
+func migrateMark(c *cli, args []string) error {
+	fs := c.flags()
+	dir := migrationFlags(fs)
+	to := fs.Int("to", 0, "last version to mark as applied")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *to <= 0 {
+		return errors.New("migrate mark needs -to")
+	}
+	return c.withMigrationLock(func(conn *dbsql.Conn) error {
+		migrations, err := c.migrations(conn, *dir)
+		if err != nil {
+			return err
+		}
+		sql := "INSERT INTO schema_migrations(version,name,applied_at) VALUES($1,$2,$3)"
+		for _, m := range migrations {
+			if m.applied != nil || m.number > *to {
+				continue
+			}
+			if _, err = conn.ExecContext(c.ctx, sql, m.version, m.name, time.Now()); err != nil {
+				return err
+			}
+			fmt.Fprintf(c.out, "marked %s_%s\n", m.version, m.name)
+		}
+		return nil
+	})
+}
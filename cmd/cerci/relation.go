@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/codonex/cerci-service/client"
+)
+
+// relationNode is a relation with the relations below it, the format of
+// cmd/models/models. A node without a type has the type of its parent.
+type relationNode struct {
+	Name     string
+	Type     string          `json:",omitempty"`
+	Children []*relationNode `json:",omitempty"`
+}
+
+// importRelations creates the relations of a tree file through the API, parents
+// before their children
+func importRelations(c *cli, args []string) error {
+	fs := c.flags()
+	file := fs.String("f", "", "JSON file of the relation tree, - for stdin")
+	parent := fs.Int("parent", 0, "path id of the relation to import below, the root when 0")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("relation import needs -f")
+	}
+	var nodes []*relationNode
+	if err := readJSON(*file, &nodes); err != nil {
+		return err
+	}
+
+	count := 0
+	var create func(nodes []*relationNode, parentID int, parentType string, path string) error
+	create = func(nodes []*relationNode, parentID int, parentType string, path string) error {
+		for _, node := range nodes {
+			relationType := node.Type
+			if relationType == "" {
+				relationType = parentType
+			}
+			nodePath := strings.TrimPrefix(path+"/"+node.Name, "/")
+			if relationType == "" {
+				return fmt.Errorf("%s has no type", nodePath)
+			}
+			pathID, err := createRelation(c, client.RelationRequest{Name: node.Name, Type: relationType, ParentID: parentID})
+			if err != nil {
+				return fmt.Errorf("%s: %v", nodePath, err)
+			}
+			count++
+			if err = create(node.Children, pathID, relationType, nodePath); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err := create(nodes, *parent, "", "")
+	fmt.Fprintf(c.out, "created %d relations\n", count)
+	return err
+}
+
+// createRelation creates one relation through the bulk endpoint, which reports
+// the path id its children need and refuses duplicates, and returns its path id
+func createRelation(c *cli, request client.RelationRequest) (int, error) {
+	operations := []client.RelationBulkOperation{{Op: "create", Data: &request}}
+	response, err := c.client().BulkRelations(c.ctx, operations, url.Values{"mode": {"per_item"}})
+	if err != nil {
+		return 0, err
+	}
+	if len(response.Results) != 1 {
+		return 0, fmt.Errorf("bulk response has %d results", len(response.Results))
+	}
+	result := response.Results[0]
+	if result.Error != "" {
+		return 0, errors.New(result.Error)
+	}
+	data, _ := result.Data.(map[string]interface{})
+	pathID, ok := data["path_id"].(float64)
+	if !ok {
+		return 0, errors.New("bulk response has no path_id")
+	}
+	return int(pathID), nil
+}
+
+// exportRelations writes the relation tree, or the tree below a relation, from
+// the database in the format read by import
+func exportRelations(c *cli, args []string) error {
+	fs := c.flags()
+	root := fs.Int("root", 0, "path id of the relation to export with the relations below it, all when 0")
+	output := fs.String("o", "", "file to write, stdout when empty")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	db, err := c.database()
+	if err != nil {
+		return err
+	}
+
+	sql := "SELECT r.name,t.name,r.path::text FROM relation r JOIN relation_type t ON t.id=r.type_id WHERE r.path IS NOT NULL"
+	var queryArgs []interface{}
+	if *root != 0 {
+		sql += " AND r.path operator(public.<@) (SELECT path FROM relation WHERE path_id=$1)"
+		queryArgs = append(queryArgs, *root)
+	}
+	rows, err := db.QueryContext(c.ctx, sql+" ORDER BY public.nlevel(r.path), r.path_id", queryArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var tree []*relationNode
+	byPath := map[string]*relationNode{}
+	for rows.Next() {
+		var name, relationType, path string
+		if err = rows.Scan(&name, &relationType, &path); err != nil {
+			return err
+		}
+		node := &relationNode{Name: name, Type: relationType}
+		byPath[path] = node
+		var parent *relationNode
+		if i := strings.LastIndex(path, "."); i >= 0 {
+			parent = byPath[path[:i]]
+		}
+		if parent != nil {
+			parent.Children = append(parent.Children, node)
+		} else {
+			tree = append(tree, node)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *output == "" {
+		_, err = c.out.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(*output, data, 0644)
+}
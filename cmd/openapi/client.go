@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// clientHeader starts the generated files of the client package
+const clientHeader = "// Code generated by go run ./cmd/openapi; DO NOT EDIT.\n\npackage client\n\n"
+
+// writeClient generates the types and the methods of the client package in
+// dir. It must run after spec, which collects the component types.
+func (g *generator) writeClient(dir string) error {
+	routes, err := g.routes()
+	if err != nil {
+		return err
+	}
+
+	var methods bytes.Buffer
+	imports := map[string]bool{"context": true}
+	for _, r := range routes {
+		g.clientMethod(&methods, r, imports)
+	}
+	if err = writeGoFile(filepath.Join(dir, "api_gen.go"), imports, methods.Bytes()); err != nil {
+		return err
+	}
+
+	var typeDecls bytes.Buffer
+	imports = map[string]bool{}
+	for _, name := range g.clientTypes() {
+		g.clientType(&typeDecls, g.pkg.Scope().Lookup(name).(*types.TypeName), imports)
+	}
+	return writeGoFile(filepath.Join(dir, "types_gen.go"), imports, typeDecls.Bytes())
+}
+
+// writeGoFile writes the generated declarations with their imports to path
+func writeGoFile(path string, imports map[string]bool, decls []byte) error {
+	var out bytes.Buffer
+	out.WriteString(clientHeader)
+	if len(imports) > 0 {
+		var paths []string
+		for path := range imports {
+			paths = append(paths, strconv.Quote(path))
+		}
+		sort.Strings(paths)
+		out.WriteString("import (\n" + strings.Join(paths, "\n") + "\n)\n\n")
+	}
+	out.Write(decls)
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return ioutil.WriteFile(path, source, 0644)
+}
+
+// clientTypes returns the names of the structs the client needs: the schema
+// components and the structs they embed. ErrorResponse is decoded into the
+// Error of the client instead.
+func (g *generator) clientTypes() []string {
+	names := map[string]bool{}
+	var add func(name string)
+	add = func(name string) {
+		obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || names[name] || name == "ErrorResponse" {
+			return
+		}
+		names[name] = true
+		if t, ok := obj.Type().Underlying().(*types.Struct); ok {
+			for i := 0; i < t.NumFields(); i++ {
+				if named, ok := t.Field(i).Type().(*types.Named); ok && named.Obj().Pkg() == g.pkg {
+					add(named.Obj().Name())
+				}
+			}
+		}
+	}
+	for name := range g.schemas {
+		add(name)
+	}
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// clientType writes the declaration of a struct of the service
+func (g *generator) clientType(out *bytes.Buffer, obj *types.TypeName, imports map[string]bool) {
+	writeComment(out, "", typeComment(g.typeDocs[obj.Name()]))
+	fmt.Fprintf(out, "type %s struct {\n", obj.Name())
+	t := obj.Type().Underlying().(*types.Struct)
+	for i := 0; i < t.NumFields(); i++ {
+		field := t.Field(i)
+		if !field.Exported() {
+			continue
+		}
+		writeComment(out, "\t", g.fieldDocs[field.Pos()])
+		if field.Embedded() {
+			out.WriteString("\t" + g.goType(field.Type(), imports))
+		} else {
+			out.WriteString("\t" + field.Name() + " " + g.goType(field.Type(), imports))
+		}
+		if tag := t.Tag(i); tag != "" {
+			out.WriteString(" `" + tag + "`")
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("}\n\n")
+}
+
+// clientMethod writes the method of the client calling route r
+func (g *generator) clientMethod(out *bytes.Buffer, r route, imports map[string]bool) {
+	u := g.usage(r)
+	name := r.handler.Name.Name
+
+	params := []string{"ctx context.Context"}
+	path := ""
+	last := 0
+	for _, match := range pathParam.FindAllStringSubmatchIndex(r.path, -1) {
+		param := goName(r.path[match[2]:match[3]])
+		params = append(params, param+" string")
+		path += strconv.Quote(r.path[last:match[0]]) + " + url.PathEscape(" + param + ") + "
+		last = match[1]
+		imports["net/url"] = true
+	}
+	path = strings.TrimSuffix(path+strconv.Quote(r.path[last:]), ` + ""`)
+	request := fmt.Sprintf("request{method: %q, path: %s", r.method, path)
+	if u.requestBody != nil {
+		params = append(params, "body "+g.goType(u.requestBody, imports))
+	}
+	if len(u.query) > 0 {
+		params = append(params, "query url.Values")
+		request += ", query: query"
+		imports["net/url"] = true
+	}
+	if u.requestBody != nil {
+		request += ", body: body"
+	}
+	request += "}"
+	params = append(params, "opts ...RequestOption")
+
+	summary, _ := g.handlerDoc(r.handler)
+	comment := fmt.Sprintf("%s calls %s %s: %s.", name, r.method, r.path, lowerFirst(summary))
+	if len(u.query) > 0 {
+		comment += " Query parameters: " + strings.Join(u.query, ", ") + "."
+	}
+	if u.auth == "required" {
+		comment += " Requires an API key or token."
+	}
+	writeComment(out, "", comment)
+
+	signature := fmt.Sprintf("func (c *Client) %s(%s)", name, strings.Join(params, ", "))
+	result := successType(u)
+	switch {
+	case r.raw:
+		imports["io"] = true
+		fmt.Fprintf(out, "%s (io.ReadCloser, error) {\n\treturn c.stream(ctx, %s, opts)\n}\n\n", signature, request)
+	case result == nil:
+		fmt.Fprintf(out, "%s error {\n\treturn c.call(ctx, %s, nil, opts)\n}\n\n", signature, request)
+	default:
+		typeName := g.goType(result, imports)
+		if _, isStruct := result.Underlying().(*types.Struct); isStruct {
+			fmt.Fprintf(out, "%s (*%s, error) {\n\tvar out %s\n\tif err := c.call(ctx, %s, &out, opts); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n}\n\n",
+				signature, typeName, typeName, request)
+		} else {
+			fmt.Fprintf(out, "%s (%s, error) {\n\tvar out %s\n\terr := c.call(ctx, %s, &out, opts)\n\treturn out, err\n}\n\n",
+				signature, typeName, typeName, request)
+		}
+	}
+}
+
+// successType returns the body type of the lowest 2xx status of u
+func successType(u *usage) types.Type {
+	var statuses []int
+	for status := range u.responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		if status >= 200 && status < 300 && u.responses[status] != nil {
+			return u.responses[status]
+		}
+	}
+	return nil
+}
+
+// goType returns the Go type of t in the client package. Structs of the
+// service keep their name, other types of the service become their underlying
+// type and error values, which marshal as objects, raw JSON.
+func (g *generator) goType(t types.Type, imports map[string]bool) string {
+	switch t := t.(type) {
+	case *types.Named:
+		obj := t.Obj()
+		switch {
+		case obj.Pkg() == nil:
+			imports["encoding/json"] = true
+			return "json.RawMessage"
+		case obj.Pkg() == g.pkg:
+			if _, isStruct := t.Underlying().(*types.Struct); isStruct {
+				return obj.Name()
+			}
+			return g.goType(t.Underlying(), imports)
+		}
+		imports[obj.Pkg().Path()] = true
+		return obj.Pkg().Name() + "." + obj.Name()
+	case *types.Pointer:
+		return "*" + g.goType(t.Elem(), imports)
+	case *types.Slice:
+		return "[]" + g.goType(t.Elem(), imports)
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.goType(t.Elem(), imports))
+	case *types.Map:
+		return "map[" + g.goType(t.Key(), imports) + "]" + g.goType(t.Elem(), imports)
+	case *types.Struct:
+		var fields []string
+		for i := 0; i < t.NumFields(); i++ {
+			field := t.Field(i)
+			if !field.Exported() {
+				continue
+			}
+			declared := field.Name() + " " + g.goType(field.Type(), imports)
+			if tag := t.Tag(i); tag != "" {
+				declared += " `" + tag + "`"
+			}
+			fields = append(fields, declared)
+		}
+		return "struct {" + strings.Join(fields, "; ") + "}"
+	case *types.Interface:
+		return "interface{}"
+	case *types.Basic:
+		return t.Name()
+	}
+	return "interface{}"
+}
+
+// typeComment returns a type doc comment without swagger annotations
+func typeComment(doc string) string {
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "swagger:") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// writeComment writes text as a line comment with the given indent, wrapping
+// lines longer than 80 columns
+func writeComment(out *bytes.Buffer, indent string, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		comment := indent + "//"
+		for _, word := range strings.Fields(line) {
+			if len(comment)+1+len(word) > 80 && comment != indent+"//" {
+				out.WriteString(comment + "\n")
+				comment = indent + "//"
+			}
+			comment += " " + word
+		}
+		out.WriteString(comment + "\n")
+	}
+}
+
+// goName turns a path parameter such as news_id into a Go name, newsID
+func goName(param string) string {
+	parts := strings.Split(param, "_")
+	name := parts[0]
+	for _, part := range parts[1:] {
+		if part == "id" {
+			name += "ID"
+		} else if part != "" {
+			name += upperFirst(part)
+		}
+	}
+	return name
+}
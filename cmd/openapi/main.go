@@ -0,0 +1,820 @@
+// Command openapi generates the OpenAPI document of the service from its
+// source code: the routes registered in AddRoutes, the doc comments of their
+// handlers and the types annotated with swagger:model or swagger:response.
+// With -client it also generates the types and methods of the Go client.
+//
+// Request bodies, responses, error statuses, query parameters and headers are
+// found by following each handler through the helpers it calls. Run it with
+// go generate from the repository root, it writes the document as a Go
+// constant so that the binary serves the spec it was built with.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// jsonMediaTypes are the formats negotiated by AddRoute, see negotiate.go
+var jsonMediaTypes = []string{"application/json", "application/xml", "text/csv"}
+
+// requestMediaTypes are the request body formats accepted by unmarshalRequest
+var requestMediaTypes = []string{"application/json", "application/xml"}
+
+// authHeaders are described by the security schemes instead of parameters
+var authHeaders = map[string]bool{"X-API-Key": true, "Authorization": true, "Content-Type": true}
+
+var pathParam = regexp.MustCompile(`\{([^}:]+)(?::([^}]+))?\}`)
+
+type route struct {
+	method  string
+	path    string
+	raw     bool
+	handler *ast.FuncDecl
+}
+
+// usage is what a handler does with the request and the response
+type usage struct {
+	requestBody  types.Type
+	responses    map[int]types.Type
+	errors       map[int]bool
+	query        []string
+	headers      []string
+	contentTypes []string
+	auth         string
+}
+
+// binding is what is known about a variable: the constant strings it may hold,
+// and the type of the argument when it is an interface parameter
+type binding struct {
+	values []string
+	typ    types.Type
+}
+
+type bindings map[types.Object]binding
+
+type generator struct {
+	fset      *token.FileSet
+	files     []*ast.File
+	pkg       *types.Package
+	info      *types.Info
+	decls     map[types.Object]*ast.FuncDecl
+	fieldDocs map[token.Pos]string
+	typeDocs  map[string]string
+	schemas   map[string]interface{}
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the service package")
+	output := flag.String("o", "openapi_gen.go", "generated Go file")
+	client := flag.String("client", "", "directory of the client package to generate, none when empty")
+	flag.Parse()
+
+	g, err := load(*dir)
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", *dir, err)
+	}
+	spec, err := g.spec()
+	if err != nil {
+		log.Fatalf("Failed to generate spec: %v", err)
+	}
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode spec: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by go run ./cmd/openapi; DO NOT EDIT.\n\npackage main\n\n")
+	out.WriteString("// openAPISpec is the OpenAPI document of the service, served at /openapi.json\n")
+	out.WriteString("const openAPISpec = `" + strings.Replace(string(data), "`", "` + \"`\" + `", -1) + "\n`\n")
+	if err = ioutil.WriteFile(*output, out.Bytes(), 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+
+	if *client != "" {
+		if err = g.writeClient(*client); err != nil {
+			log.Fatalf("Failed to generate client: %v", err)
+		}
+	}
+}
+
+// load parses and type checks the package in dir
+func load(dir string) (*generator, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{
+		fset:      token.NewFileSet(),
+		decls:     map[types.Object]*ast.FuncDecl{},
+		fieldDocs: map[token.Pos]string{},
+		typeDocs:  map[string]string{},
+		schemas:   map[string]interface{}{},
+		info: &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+		},
+	}
+	for _, name := range bp.GoFiles {
+		file, err := parser.ParseFile(g.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		g.files = append(g.files, file)
+	}
+	// Type errors are reported but do not stop the generator, the package does
+	// not compile before the generated file exists
+	conf := types.Config{
+		Importer: importer.ForCompiler(g.fset, "source", nil),
+		Error:    func(err error) { log.Printf("Type error: %v", err) },
+	}
+	g.pkg, _ = conf.Check(bp.Name, g.fset, g.files, g.info)
+
+	for _, file := range g.files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				g.decls[g.info.Defs[decl.Name]] = decl
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					doc := typeSpec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					g.typeDocs[typeSpec.Name.Name] = commentText(doc)
+				}
+			}
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if field, ok := n.(*ast.Field); ok {
+				text := field.Doc.Text()
+				if text == "" {
+					text = field.Comment.Text()
+				}
+				for _, name := range field.Names {
+					g.fieldDocs[name.Pos()] = text
+				}
+			}
+			return true
+		})
+	}
+	return g, nil
+}
+
+// spec builds the OpenAPI document
+func (g *generator) spec() (map[string]interface{}, error) {
+	routes, err := g.routes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Annotated types are part of the document even when no route uses them
+	var annotated []string
+	for name, doc := range g.typeDocs {
+		if strings.Contains(doc, "swagger:model") || strings.Contains(doc, "swagger:response") {
+			annotated = append(annotated, name)
+		}
+	}
+	sort.Strings(annotated)
+	for _, name := range annotated {
+		if obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName); ok {
+			g.schema(obj.Type())
+		}
+	}
+
+	paths := map[string]interface{}{}
+	tags := map[string]bool{}
+	for _, r := range routes {
+		path, parameters := openAPIPath(r.path)
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		operation := g.operation(r, parameters)
+		item[strings.ToLower(r.method)] = operation
+		for _, tag := range operation["tags"].([]string) {
+			tags[tag] = true
+		}
+	}
+	var tagList []map[string]string
+	for tag := range tags {
+		tagList = append(tagList, map[string]string{"name": tag})
+	}
+	sort.Slice(tagList, func(i, j int) bool { return tagList[i]["name"] < tagList[j]["name"] })
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Cerci API",
+			"version":     "0.0.1",
+			"description": "Generated from the routes and annotated types of the service by cmd/openapi.",
+		},
+		"tags":  tagList,
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]interface{}{
+				"apiKey": map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"bearer": map[string]string{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}, nil
+}
+
+// routes returns the routes registered in AddRoutes, in registration order
+func (g *generator) routes() ([]route, error) {
+	var addRoutes *ast.FuncDecl
+	for _, decl := range g.decls {
+		if decl.Name.Name == "AddRoutes" && decl.Recv != nil {
+			addRoutes = decl
+		}
+	}
+	if addRoutes == nil {
+		return nil, fmt.Errorf("AddRoutes not found")
+	}
+
+	var routes []route
+	var err error
+	ast.Inspect(addRoutes.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || err != nil {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "AddRoute" && sel.Sel.Name != "AddRawRoute") || len(call.Args) != 3 {
+			return true
+		}
+		r := route{raw: sel.Sel.Name == "AddRawRoute"}
+		r.method, r.path = g.stringValue(call.Args[0], nil), g.stringValue(call.Args[1], nil)
+		if handler, ok := call.Args[2].(*ast.SelectorExpr); ok {
+			r.handler = g.decls[g.info.Uses[handler.Sel]]
+		}
+		if r.method == "" || r.path == "" || r.handler == nil {
+			err = fmt.Errorf("%s: cannot resolve route", g.fset.Position(call.Pos()))
+			return false
+		}
+		routes = append(routes, r)
+		return true
+	})
+	return routes, err
+}
+
+// openAPIPath turns a mux path template into an OpenAPI path and its parameters
+func openAPIPath(template string) (string, []interface{}) {
+	var parameters []interface{}
+	path := pathParam.ReplaceAllStringFunc(template, func(match string) string {
+		groups := pathParam.FindStringSubmatch(match)
+		schema := map[string]interface{}{"type": "string"}
+		if groups[2] != "" {
+			schema["pattern"] = "^" + groups[2] + "$"
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name": groups[1], "in": "path", "required": true, "schema": schema,
+		})
+		return "{" + groups[1] + "}"
+	})
+	return path, parameters
+}
+
+// usage follows the handler of a route
+func (g *generator) usage(r route) *usage {
+	u := &usage{responses: map[int]types.Type{}, errors: map[int]bool{}}
+	g.walk(r.handler, nil, u, map[*ast.FuncDecl]bool{})
+	return u
+}
+
+// operation describes the handler of a route
+func (g *generator) operation(r route, parameters []interface{}) map[string]interface{} {
+	u := g.usage(r)
+
+	summary, description := g.handlerDoc(r.handler)
+	tag := strings.SplitN(strings.TrimPrefix(r.path, "/"), "/", 2)[0]
+	operation := map[string]interface{}{
+		"operationId": lowerFirst(r.handler.Name.Name),
+		"summary":     summary,
+		"tags":        []string{tag},
+	}
+	if description != "" && description != summary {
+		operation["description"] = description
+	}
+
+	for _, name := range u.query {
+		parameters = append(parameters, map[string]interface{}{"name": name, "in": "query", "schema": map[string]string{"type": "string"}})
+	}
+	for _, name := range u.headers {
+		parameters = append(parameters, map[string]interface{}{"name": name, "in": "header", "schema": map[string]string{"type": "string"}})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if u.requestBody != nil {
+		content := map[string]interface{}{}
+		for _, mediaType := range requestMediaTypes {
+			content[mediaType] = map[string]interface{}{"schema": g.schema(u.requestBody)}
+		}
+		operation["requestBody"] = map[string]interface{}{"required": true, "content": content}
+	}
+
+	if r.raw && u.responses[http.StatusOK] == nil {
+		// raw handlers may write their body without an explicit status
+		u.responses[http.StatusOK] = nil
+	}
+	responses := map[string]interface{}{}
+	for status, data := range u.responses {
+		response := map[string]interface{}{"description": http.StatusText(status)}
+		content := map[string]interface{}{}
+		switch {
+		case status == http.StatusNotModified:
+		case r.raw:
+			mediaTypes := u.contentTypes
+			if len(mediaTypes) == 0 {
+				mediaTypes = []string{"application/octet-stream"}
+			}
+			for _, mediaType := range mediaTypes {
+				content[mediaType] = map[string]interface{}{"schema": map[string]string{"type": "string", "format": "binary"}}
+			}
+		case data != nil:
+			for _, mediaType := range jsonMediaTypes {
+				content[mediaType] = map[string]interface{}{"schema": g.schema(data)}
+			}
+		}
+		if len(content) > 0 {
+			response["content"] = content
+		}
+		responses[strconv.Itoa(status)] = response
+	}
+	for status := range u.errors {
+		responses[strconv.Itoa(status)] = map[string]interface{}{
+			"description": http.StatusText(status),
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": g.schema(g.pkg.Scope().Lookup("ErrorResponse").Type())},
+			},
+		}
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]interface{}{"description": http.StatusText(http.StatusOK)}
+	}
+	operation["responses"] = responses
+
+	switch u.auth {
+	case "required":
+		operation["security"] = []interface{}{map[string][]string{"apiKey": {}}, map[string][]string{"bearer": {}}}
+	case "optional":
+		operation["security"] = []interface{}{map[string][]string{"apiKey": {}}, map[string][]string{"bearer": {}}, map[string][]string{}}
+	}
+	return operation
+}
+
+// handlerDoc returns the first sentence and the full text of a handler doc
+// comment, without the leading handler name
+func (g *generator) handlerDoc(decl *ast.FuncDecl) (string, string) {
+	text := strings.Join(strings.Fields(decl.Doc.Text()), " ")
+	text = strings.TrimPrefix(text, decl.Name.Name+" ")
+	if text == "" {
+		return decl.Name.Name, ""
+	}
+	text = upperFirst(text)
+	summary := text
+	if i := strings.Index(summary, ". "); i >= 0 {
+		summary = summary[:i]
+	}
+	return strings.TrimSuffix(summary, "."), text
+}
+
+// walk records what decl does with the request and response, following the
+// functions of the package it calls. b holds what is known about the
+// arguments decl was called with.
+func (g *generator) walk(decl *ast.FuncDecl, b bindings, u *usage, visited map[*ast.FuncDecl]bool) {
+	if decl.Body == nil || visited[decl] {
+		return
+	}
+	visited[decl] = true
+	if b == nil {
+		b = bindings{}
+	}
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.RangeStmt:
+			g.bindRange(n, b)
+		case *ast.CallExpr:
+			g.call(n, b, u, visited)
+		case *ast.IndexExpr:
+			if g.isType(n.X, "net/url.Values") {
+				u.query = appendUnique(u.query, g.stringValues(n.Index, b)...)
+			}
+		case *ast.CompositeLit:
+			if g.isType(n, "ErrorResponse") {
+				for _, elt := range n.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok && isIdent(kv.Key, "Status") {
+						if status := g.intValue(kv.Value); status >= 400 {
+							u.errors[status] = true
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// call records a single call of walk
+func (g *generator) call(call *ast.CallExpr, b bindings, u *usage, visited map[*ast.FuncDecl]bool) {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+		switch {
+		case ident.Name == "Get" && g.isType(fun.X, "net/url.Values") && len(call.Args) == 1:
+			u.query = appendUnique(u.query, g.stringValues(call.Args[0], b)...)
+		case ident.Name == "Get" && g.isType(fun.X, "net/http.Header") && isRequestHeader(fun.X) && len(call.Args) == 1:
+			for _, name := range g.stringValues(call.Args[0], b) {
+				if !authHeaders[name] {
+					u.headers = appendUnique(u.headers, name)
+				}
+			}
+		case ident.Name == "WriteHeader" && g.isType(fun.X, "net/http.ResponseWriter") && len(call.Args) == 1:
+			if status := g.intValue(call.Args[0]); status > 0 && status < 400 {
+				if _, ok := u.responses[status]; !ok {
+					u.responses[status] = nil
+				}
+			}
+		case ident.Name == "Set" && g.isType(fun.X, "net/http.Header") && len(call.Args) == 2 &&
+			g.stringValue(call.Args[0], b) == "Content-Type":
+			for _, mediaType := range g.stringValues(call.Args[1], b) {
+				u.contentTypes = appendUnique(u.contentTypes, strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+			}
+		}
+	}
+	if ident == nil {
+		return
+	}
+	obj := g.info.Uses[ident]
+	if obj == nil || obj.Pkg() != g.pkg {
+		return
+	}
+
+	switch obj.Name() {
+	case "unmarshalRequest":
+		if t := g.exprType(call.Args[2], b); t != nil {
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			u.requestBody = t
+		}
+		return
+	case "RenderJson":
+		status := g.intValue(call.Args[1])
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status < 400 {
+			u.responses[status] = g.dataType(call.Args[2], b)
+		}
+		return
+	case "renderVersioned":
+		u.responses[http.StatusOK] = g.dataType(call.Args[3], b)
+		u.responses[http.StatusNotModified] = nil
+		return
+	case "RenderErrorResponse":
+		if status := g.intValue(call.Args[1]); status >= 400 {
+			u.errors[status] = true
+		}
+		return
+	case "RenderError", "renderNegotiated":
+		return
+	case "requireEditor", "requireAdmin":
+		u.auth = "required"
+	case "authenticate":
+		if u.auth == "" {
+			u.auth = "optional"
+		}
+	}
+
+	if decl := g.decls[obj]; decl != nil {
+		g.walk(decl, g.bind(decl, call, b), u, visited)
+	}
+}
+
+// bind returns what is known about the arguments of call, by parameter of decl
+func (g *generator) bind(decl *ast.FuncDecl, call *ast.CallExpr, b bindings) bindings {
+	bound := bindings{}
+	i := 0
+	for _, field := range decl.Type.Params.List {
+		for _, name := range field.Names {
+			if i < len(call.Args) {
+				param := binding{values: g.stringValues(call.Args[i], b)}
+				if _, isInterface := g.info.Defs[name].Type().Underlying().(*types.Interface); isInterface {
+					param.typ = g.exprType(call.Args[i], b)
+				}
+				bound[g.info.Defs[name]] = param
+			}
+			i++
+		}
+	}
+	return bound
+}
+
+// bindRange binds the key and value variables of a range over a literal to
+// the constant strings of the literal
+func (g *generator) bindRange(stmt *ast.RangeStmt, b bindings) {
+	literal, ok := stmt.X.(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	var keys, values []string
+	for _, elt := range literal.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			keys = append(keys, g.stringValues(kv.Key, b)...)
+			values = append(values, g.stringValues(kv.Value, b)...)
+		} else {
+			values = append(values, g.stringValues(elt, b)...)
+		}
+	}
+	if _, isMap := g.info.Types[literal].Type.Underlying().(*types.Map); isMap {
+		g.bindIdent(stmt.Key, keys, b)
+	}
+	g.bindIdent(stmt.Value, values, b)
+}
+
+func (g *generator) bindIdent(expr ast.Expr, values []string, b bindings) {
+	if ident, ok := expr.(*ast.Ident); ok && len(values) > 0 {
+		if obj := g.info.Defs[ident]; obj != nil {
+			b[obj] = binding{values: values}
+		}
+	}
+}
+
+// exprType returns the type of expr, or of the argument bound to it
+func (g *generator) exprType(expr ast.Expr, b bindings) types.Type {
+	if ident, ok := expr.(*ast.Ident); ok {
+		if bound := b[g.info.Uses[ident]]; bound.typ != nil {
+			return bound.typ
+		}
+	}
+	return g.info.Types[expr].Type
+}
+
+// dataType returns the type of rendered data, nil for a nil literal
+func (g *generator) dataType(expr ast.Expr, b bindings) types.Type {
+	t := g.exprType(expr, b)
+	if basic, ok := t.(*types.Basic); ok && basic.Kind() == types.UntypedNil {
+		return nil
+	}
+	return t
+}
+
+// stringValues returns the constant strings expr may hold
+func (g *generator) stringValues(expr ast.Expr, b bindings) []string {
+	if value := g.info.Types[expr].Value; value != nil && value.Kind() == constant.String {
+		return []string{constant.StringVal(value)}
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return b[g.info.Uses[ident]].values
+	}
+	return nil
+}
+
+// stringValue returns the constant string value of expr
+func (g *generator) stringValue(expr ast.Expr, b bindings) string {
+	if values := g.stringValues(expr, b); len(values) == 1 {
+		return values[0]
+	}
+	return ""
+}
+
+// intValue returns the constant integer value of expr, 0 when it is not constant
+func (g *generator) intValue(expr ast.Expr) int {
+	if value := g.info.Types[expr].Value; value != nil && value.Kind() == constant.Int {
+		if i, ok := constant.Int64Val(value); ok {
+			return int(i)
+		}
+	}
+	return 0
+}
+
+// isType reports whether expr has the named type, given as path.Name or as
+// the name of a type of the package
+func (g *generator) isType(expr ast.Expr, name string) bool {
+	t := g.info.Types[expr].Type
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	if named.Obj().Pkg() == g.pkg {
+		return named.Obj().Name() == name
+	}
+	return named.Obj().Pkg().Path()+"."+named.Obj().Name() == name
+}
+
+// isRequestHeader reports whether expr is the Header field of a request,
+// rather than the header of the response
+func isRequestHeader(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Header"
+}
+
+// schema returns the JSON schema of t, named structs of the package become
+// components
+func (g *generator) schema(t types.Type) interface{} {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return nullable(g.schema(t.Elem()))
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil {
+			// error values marshal as an empty object
+			return map[string]interface{}{"type": "object", "nullable": true}
+		}
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "time.Time":
+			return map[string]string{"type": "string", "format": "date-time"}
+		case "encoding/json.RawMessage":
+			return map[string]string{}
+		}
+		if _, ok := t.Underlying().(*types.Struct); ok && obj.Pkg() == g.pkg {
+			if _, done := g.schemas[obj.Name()]; !done {
+				g.schemas[obj.Name()] = nil
+				schema := g.structSchema(t.Underlying().(*types.Struct))
+				if doc := typeDescription(g.typeDocs[obj.Name()]); doc != "" {
+					schema["description"] = doc
+				}
+				g.schemas[obj.Name()] = schema
+			}
+			return map[string]string{"$ref": "#/components/schemas/" + obj.Name()}
+		}
+		return g.schema(t.Underlying())
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return map[string]string{"type": "boolean"}
+		case t.Info()&types.IsInteger != 0:
+			if t.Kind() == types.Int64 || t.Kind() == types.Uint64 {
+				return map[string]string{"type": "integer", "format": "int64"}
+			}
+			return map[string]string{"type": "integer"}
+		case t.Info()&types.IsFloat != 0:
+			return map[string]string{"type": "number"}
+		default:
+			return map[string]string{"type": "string"}
+		}
+	case *types.Slice:
+		if basic, ok := t.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return map[string]interface{}{"type": "string", "format": "byte", "nullable": true}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem()), "nullable": true}
+	case *types.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case *types.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem()), "nullable": true}
+	case *types.Struct:
+		return g.structSchema(t)
+	}
+	return map[string]string{}
+}
+
+// structSchema returns the object schema of the json fields of t
+func (g *generator) structSchema(t *types.Struct) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumFields(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(reflect.StructTag(t.Tag(i)).Get("json"), ",")
+		if !field.Exported() || tag[0] == "-" {
+			continue
+		}
+		if field.Embedded() && tag[0] == "" {
+			if embedded, ok := field.Type().Underlying().(*types.Struct); ok {
+				for name, property := range g.structSchema(embedded)["properties"].(map[string]interface{}) {
+					properties[name] = property
+				}
+				continue
+			}
+		}
+		name := tag[0]
+		if name == "" {
+			name = field.Name()
+		}
+		property := g.schema(field.Type())
+		if doc := strings.TrimSpace(g.fieldDocs[field.Pos()]); doc != "" {
+			if ref, ok := property.(map[string]string); !ok || ref["$ref"] == "" {
+				property = withKey(property, "description", strings.Join(strings.Fields(doc), " "))
+			}
+		}
+		properties[name] = property
+	}
+	// encoding/json ignores unknown fields, the spec rejects them to catch typos
+	return map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}
+}
+
+// nullable returns schema allowing null, the JSON of nil pointers, slices and maps
+func nullable(schema interface{}) interface{} {
+	if ref, ok := schema.(map[string]string); ok && ref["$ref"] != "" {
+		return map[string]interface{}{"allOf": []interface{}{ref}, "nullable": true}
+	}
+	if any, ok := schema.(map[string]string); ok && len(any) == 0 {
+		return schema
+	}
+	return withKey(schema, "nullable", true)
+}
+
+// withKey returns a copy of schema with key set to value
+func withKey(schema interface{}, key string, value interface{}) map[string]interface{} {
+	copied := map[string]interface{}{}
+	switch schema := schema.(type) {
+	case map[string]string:
+		for k, v := range schema {
+			copied[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range schema {
+			copied[k] = v
+		}
+	}
+	copied[key] = value
+	return copied
+}
+
+// typeDescription returns a type doc comment without swagger annotations
+func typeDescription(doc string) string {
+	var lines []string
+	for _, line := range strings.Split(doc, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "swagger:") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+func appendUnique(values []string, added ...string) []string {
+	for _, value := range added {
+		if value != "" && !contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+func lowerFirst(s string) string {
+	return string(unicode.ToLower(rune(s[0]))) + s[1:]
+}
+
+func upperFirst(s string) string {
+	return string(unicode.ToUpper(rune(s[0]))) + s[1:]
+}
+
+// commentText returns the text of a comment group including directive lines
+// such as //swagger:model, which CommentGroup.Text drops
+func commentText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	var lines []string
+	for _, comment := range doc.List {
+		lines = append(lines, strings.TrimSpace(strings.TrimPrefix(comment.Text, "//")))
+	}
+	return strings.Join(lines, "\n")
+}
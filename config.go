@@ -0,0 +1,321 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// LogConfig is config struct for logging
+type LogConfig struct {
+	// panic, fatal, error, warn, info (default), debug or trace
+	Level string `yaml:"level" envconfig:"LOG_LEVEL"`
+}
+
+// apply sets the level of the logger
+func (c LogConfig) apply() {
+	level, err := logrus.ParseLevel(c.Level)
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	logrus.SetLevel(level)
+}
+
+// redacted replaces the values of secret settings when printing the config
+const redacted = "REDACTED"
+
+// configProblems collects the problems of a config by the yaml path of the setting
+type configProblems []string
+
+func (p *configProblems) add(path string, format string, args ...interface{}) {
+	*p = append(*p, path+": "+fmt.Sprintf(format, args...))
+}
+
+// Validate checks the settings of the config and reports every problem found
+func (c *Config) Validate() error {
+	var problems configProblems
+
+	if c.ServerConfig.Addr == "" {
+		problems.add("server.listen_addr", "is required")
+	}
+	for _, timeout := range c.ServerConfig.timeouts() {
+		if *timeout.value < time.Millisecond {
+			problems.add("server.timeout."+timeout.name, "is %v, write durations with a unit such as 10s", *timeout.value)
+		}
+	}
+	if c.DBConfig.User == "" {
+		problems.add("database.user", "is required")
+	}
+	if c.DBConfig.DbName == "" {
+		problems.add("database.name", "is required")
+	}
+
+	if c.Log.Level != "" {
+		if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+			problems.add("log.level", "unknown level %q", c.Log.Level)
+		}
+	}
+
+	if c.Notification.Enabled {
+		if _, err := mail.ParseAddress(c.Notification.From); err != nil {
+			problems.add("notification.from", "is not an email address: %v", err)
+		}
+		if c.Notification.SMTP.Host == "" {
+			problems.add("notification.smtp.host", "is required when notifications are enabled")
+		}
+		if port := c.Notification.SMTP.Port; port <= 0 || port > 65535 {
+			problems.add("notification.smtp.port", "must be between 1 and 65535")
+		}
+	}
+	if c.Notification.MaxAttempts < 0 {
+		problems.add("notification.max_attempts", "must not be negative")
+	}
+	if c.Notification.PollInterval < 0 {
+		problems.add("notification.poll_interval", "must not be negative")
+	}
+
+	for path, limit := range map[string]RateLimit{"spam.ip_limit": c.Spam.IPLimit, "spam.email_limit": c.Spam.EmailLimit} {
+		if limit.Requests < 0 || limit.Window < 0 {
+			problems.add(path, "must not be negative")
+		}
+		if limit.Requests > 0 && limit.Window == 0 {
+			problems.add(path+".window", "is required when requests is set")
+		}
+	}
+	if c.Spam.MinFillTime < 0 || c.Spam.MaxFormAge < 0 {
+		problems.add("spam", "min_fill_time and max_form_age must not be negative")
+	}
+	switch c.Spam.Captcha.Provider {
+	case "", "none":
+	case "fake":
+		if c.Spam.Captcha.FakeToken == "" {
+			problems.add("spam.captcha.fake_token", "is required by the fake provider")
+		}
+	case "recaptcha", "hcaptcha":
+		if c.Spam.Captcha.Secret == "" {
+			problems.add("spam.captcha.secret", "is required by %s", c.Spam.Captcha.Provider)
+		}
+	default:
+		problems.add("spam.captcha.provider", "unknown provider %q, use none, recaptcha, hcaptcha or fake", c.Spam.Captcha.Provider)
+	}
+
+	names := map[string]bool{}
+	for i, key := range c.Auth.APIKeys {
+		path := fmt.Sprintf("auth.api_keys[%d]", i)
+		if key.Name == "" {
+			problems.add(path+".name", "is required")
+		} else if names[key.Name] {
+			problems.add(path+".name", "%q is used by another key", key.Name)
+		}
+		names[key.Name] = true
+		if key.Key == "" {
+			problems.add(path+".key", "is required")
+		}
+		if key.Role != RoleEditor && key.Role != RoleAdmin {
+			problems.add(path+".role", "unknown role %q, use %s or %s", key.Role, RoleEditor, RoleAdmin)
+		}
+	}
+
+	if c.Content.DefaultLanguage != "" && len(c.Content.Languages) > 0 && !containsString(c.Content.Languages, c.Content.DefaultLanguage) {
+		problems.add("content.languages", "must contain the default language %q", c.Content.DefaultLanguage)
+	}
+	if c.Feed.Limit < 0 {
+		problems.add("feed.limit", "must not be negative")
+	}
+	if c.Trash.PurgeAfter < 0 || c.Trash.PurgeInterval < 0 {
+		problems.add("trash", "purge_after and purge_interval must not be negative")
+	}
+
+	for i, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || strings.Trim(parsed.Path, "/") != "" {
+			problems.add(fmt.Sprintf("cors.allowed_origins[%d]", i), "%q is not an origin such as https://www.codonex.com", origin)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteRedacted writes the config as yaml, with the values of the settings
+// tagged secret replaced and durations written like 10s
+func (c *Config) WriteRedacted(w io.Writer) error {
+	data, err := yaml.Marshal(configTree(reflect.ValueOf(*c), false))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// configTree returns v as yaml values by the yaml tags of its fields
+func configTree(v reflect.Value, secret bool) interface{} {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		var tree yaml.MapSlice
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if field.PkgPath != "" || name == "" || name == "-" {
+				continue
+			}
+			tree = append(tree, yaml.MapItem{Key: name, Value: configTree(v.Field(i), field.Tag.Get("secret") == "true")})
+		}
+		return tree
+	case reflect.Slice:
+		items := []interface{}{}
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, configTree(v.Index(i), secret))
+		}
+		return items
+	case reflect.Map:
+		var keys []string
+		for _, key := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(key.Interface()))
+		}
+		sort.Strings(keys)
+		var tree yaml.MapSlice
+		for _, key := range keys {
+			tree = append(tree, yaml.MapItem{Key: key, Value: configTree(v.MapIndex(reflect.ValueOf(key)), secret)})
+		}
+		return tree
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return configTree(v.Elem(), secret)
+	case reflect.String:
+		if secret && v.String() != "" {
+			return redacted
+		}
+	}
+	return v.Interface()
+}
+
+// reloadConfig reads the config again and applies the settings that are safe
+// to change while serving: the log level, the rate limits of the job
+// application form and the CORS origins. Other changes wait for a restart.
+func (app *App) reloadConfig() {
+	logrus.Info("Reloading config")
+	conf, err := NewConfig(app.conf.path)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reload config, keeping the current settings")
+		return
+	}
+
+	conf.Log.apply()
+	app.spamGuard.SetRateLimits(conf.Spam.IPLimit, conf.Spam.EmailLimit)
+	app.cors.set(conf.CORS)
+	logrus.WithFields(logrus.Fields{
+		"log_level":       logrus.GetLevel().String(),
+		"ip_limit":        fmt.Sprintf("%d/%v", conf.Spam.IPLimit.Requests, conf.Spam.IPLimit.Window),
+		"email_limit":     fmt.Sprintf("%d/%v", conf.Spam.EmailLimit.Requests, conf.Spam.EmailLimit.Window),
+		"allowed_origins": conf.CORS.AllowedOrigins,
+	}).Info("Config reloaded")
+
+	// app.conf keeps the settings read at startup, compare the rest with them
+	conf.AppName, conf.Log, conf.CORS = app.conf.AppName, app.conf.Log, app.conf.CORS
+	conf.Spam.IPLimit, conf.Spam.EmailLimit = app.conf.Spam.IPLimit, app.conf.Spam.EmailLimit
+	if !reflect.DeepEqual(conf, app.conf) {
+		logrus.Warn("Config has changes that are not reloaded, restart to apply them")
+	}
+}
+
+// overlayPath returns the config file of env next to path, config.production.yaml
+// for config.yaml
+func overlayPath(path string, env string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + env + ext
+}
+
+// decodeConfigFile decodes the yaml file at path onto config. Settings the
+// file leaves out keep their values, so an overlay only lists what it changes.
+// Unknown settings are an error, a misspelled key would otherwise be ignored.
+func decodeConfigFile(path string, config *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.SetStrict(true)
+	if err = decoder.Decode(config); err != nil && err != io.EOF {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// loadSecretFiles sets every setting tagged secret from the file named by its
+// File field, such as password from password_file, for secrets mounted by
+// docker or kubernetes. A file takes precedence over the value.
+func loadSecretFiles(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return loadSecretFiles(v.Elem(), path)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := loadSecretFiles(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := strings.TrimPrefix(path+"."+strings.Split(field.Tag.Get("yaml"), ",")[0], ".")
+			if field.Tag.Get("secret") != "true" {
+				if err := loadSecretFiles(v.Field(i), name); err != nil {
+					return err
+				}
+				continue
+			}
+			fileField, ok := v.Type().FieldByName(field.Name + "File")
+			if !ok {
+				return fmt.Errorf("%s: secret has no %sFile setting", name, field.Name)
+			}
+			secretFile := v.FieldByIndex(fileField.Index).String()
+			if secretFile == "" {
+				continue
+			}
+			data, err := ioutil.ReadFile(secretFile)
+			if err != nil {
+				return fmt.Errorf("%s_file: %v", name, err)
+			}
+			v.Field(i).SetString(strings.TrimRight(string(data), "\r\n"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// setEnv sets environment variables for the duration of a test, unset
+// variables are removed
+func setEnv(t *testing.T, values map[string]string) {
+	for key, value := range values {
+		previous, ok := os.LookupEnv(key)
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+		key := key
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(key, previous)
+			} else {
+				os.Unsetenv(key)
+			}
+		})
+	}
+}
+
+// writeConfigFile writes a config file into the test directory dir
+func writeConfigFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func validConfig() *Config {
+	conf := &Config{}
+	conf.DBConfig.User, conf.DBConfig.DbName = "codonex", "codonex"
+	conf.ServerConfig.setDefaults()
+	return conf
+}
+
+func TestDecodeConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"known settings", "database:\n    user: cerci\n", ""},
+		{"empty file", "", ""},
+		{"misspelled setting", "database:\n    pasword: secret\n", "field pasword not found"},
+		{"unknown section", "databse:\n    user: cerci\n", "field databse not found"},
+		{"wrong type", "feed:\n    limit: many\n", "cannot unmarshal"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeConfigFile(t, dir, "config.yaml", test.content)
+			err := decodeConfigFile(path, &Config{})
+			if test.wantErr == "" && err != nil {
+				t.Errorf("got %v", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("got %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+
+	if err = decodeConfigFile("config.yaml", &Config{}); err != nil {
+		t.Errorf("shipped config.yaml: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   []string
+	}{
+		{"valid", func(c *Config) {}, nil},
+		{"missing database", func(c *Config) { c.DBConfig = DbConfig{} },
+			[]string{"database.user: is required", "database.name: is required"}},
+		{"duration without unit", func(c *Config) { c.ServerConfig.Timeout.Write = 10 },
+			[]string{"server.timeout.write: is 10ns, write durations with a unit such as 10s"}},
+		{"log level", func(c *Config) { c.Log.Level = "loud" }, []string{`log.level: unknown level "loud"`}},
+		{"notifications", func(c *Config) { c.Notification.Enabled, c.Notification.From = true, "nobody" },
+			[]string{"notification.from: is not an email address", "notification.smtp.host: is required", "notification.smtp.port: must be between 1 and 65535"}},
+		{"rate limit without window", func(c *Config) { c.Spam.IPLimit = RateLimit{Requests: 5} },
+			[]string{"spam.ip_limit.window: is required when requests is set"}},
+		{"captcha secret", func(c *Config) { c.Spam.Captcha.Provider = "recaptcha" },
+			[]string{"spam.captcha.secret: is required by recaptcha"}},
+		{"captcha provider", func(c *Config) { c.Spam.Captcha.Provider = "turnstile" }, []string{`spam.captcha.provider: unknown provider "turnstile"`}},
+		{"api keys", func(c *Config) {
+			c.Auth.APIKeys = []APIKeyConfig{{Name: "ci", Key: "k", Role: RoleEditor}, {Name: "ci", Role: "owner"}}
+		}, []string{`auth.api_keys[1].name: "ci" is used by another key`, "auth.api_keys[1].key: is required", `auth.api_keys[1].role: unknown role "owner"`}},
+		{"default language", func(c *Config) { c.Content = ContentConfig{DefaultLanguage: "tr", Languages: []string{"en"}} },
+			[]string{`content.languages: must contain the default language "tr"`}},
+		{"negative settings", func(c *Config) { c.Feed.Limit, c.Trash.PurgeAfter = -1, -time.Hour },
+			[]string{"feed.limit: must not be negative", "trash: purge_after and purge_interval must not be negative"}},
+		{"cors origins", func(c *Config) {
+			c.CORS.AllowedOrigins = []string{"*", "https://www.codonex.com", "www.codonex.com", "https://www.codonex.com/news"}
+		}, []string{`cors.allowed_origins[2]: "www.codonex.com" is not an origin`, `cors.allowed_origins[3]: "https://www.codonex.com/news" is not an origin`}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf := validConfig()
+			test.change(conf)
+			err := conf.Validate()
+			if test.want == nil {
+				if err != nil {
+					t.Errorf("got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("got no error, want %q", test.want)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not report %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteRedacted(t *testing.T) {
+	conf := validConfig()
+	conf.DBConfig.Password = "db-secret"
+	conf.DBConfig.PasswordFile = "/run/secrets/db_password"
+	conf.Notification.SMTP.Password = "smtp-secret"
+	conf.Spam.FormSecret = "form-secret"
+	conf.Spam.Captcha.Secret = "captcha-secret"
+	conf.Spam.IPLimit = RateLimit{Requests: 5, Window: time.Hour}
+	conf.Auth.APIKeys = []APIKeyConfig{{Name: "ci", Key: "key-secret", Role: RoleEditor}}
+
+	var buf bytes.Buffer
+	if err := conf.WriteRedacted(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, secret := range []string{"db-secret", "smtp-secret", "form-secret", "captcha-secret", "key-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("output contains %s:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{
+		"password: " + redacted, "password_file: /run/secrets/db_password", "key: " + redacted, "name: ci",
+		"window: 1h0m0s", "server: 10s",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	// Empty secrets stay empty, so a missing secret shows
+	if !strings.Contains(out, "fake_token: \"\"") {
+		t.Errorf("output lacks the empty fake_token:\n%s", out)
+	}
+
+	// The output is a config file the service reads back
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = decodeConfigFile(writeConfigFile(t, dir, "config.yaml", out), &Config{}); err != nil {
+		t.Errorf("redacted config does not decode: %v", err)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	setEnv(t, map[string]string{"APP_ENV": "", "LOG_LEVEL": ""})
+	defer logrus.SetLevel(logrus.GetLevel())
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	start := "database:\n    user: codonex\n    name: codonex\nfeed:\n    limit: 10\n"
+	path := writeConfigFile(t, dir, "config.yaml", start)
+	conf, err := NewConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := newTestSpamGuard(t, conf.Spam)
+	app := &App{conf: conf, spamGuard: guard, cors: newCORSPolicy(conf.CORS)}
+	started := *conf
+
+	writeConfigFile(t, dir, "config.yaml", start+"log:\n    level: debug\n"+
+		"spam:\n    ip_limit:\n        requests: 9\n        window: 1m\n    email_limit:\n        requests: 2\n        window: 1h\n"+
+		"cors:\n    allowed_origins:\n        - https://www.codonex.com\n")
+	app.reloadConfig()
+	if logrus.GetLevel() != logrus.DebugLevel {
+		t.Errorf("log level %s, want debug", logrus.GetLevel())
+	}
+	ipLimit, emailLimit := guard.rateLimits()
+	if ipLimit != (RateLimit{Requests: 9, Window: time.Minute}) || emailLimit != (RateLimit{Requests: 2, Window: time.Hour}) {
+		t.Errorf("rate limits %+v %+v", ipLimit, emailLimit)
+	}
+	if !app.cors.allowed("https://www.codonex.com") {
+		t.Error("reloaded origin not allowed")
+	}
+	if !reflect.DeepEqual(*app.conf, started) {
+		t.Error("reload changed the config read at startup")
+	}
+
+	// An invalid config keeps the current settings
+	writeConfigFile(t, dir, "config.yaml", start+"spam:\n    ip_limit:\n        requests: 1\n        window: 1m\n    captcha:\n        provider: turnstile\n")
+	app.reloadConfig()
+	if ipLimit, _ = guard.rateLimits(); ipLimit.Requests != 9 {
+		t.Errorf("invalid config applied, ip limit %+v", ipLimit)
+	}
+}
+
+// configSourceTests run on testdata/config.yaml and its staging overlay, both
+// by NewConfig and by the config reader of cmd/cerci
+var configSourceTests = []struct {
+	name         string
+	env          map[string]string
+	passwordFile bool
+	wantUser     string
+	wantPassword string
+	wantName     string
+}{
+	{"file only", nil, false, "file_user", "file_password", "file_db"},
+	{"overlay over file", map[string]string{"APP_ENV": "staging"}, false, "file_user", "file_password", "staging_db"},
+	{"env over overlay", map[string]string{"APP_ENV": "staging", "DB_NAME": "env_db", "DB_USER": "env_user"}, false,
+		"env_user", "file_password", "env_db"},
+	{"env password", map[string]string{"DB_PASSWORD": "env_password"}, false, "file_user", "env_password", "file_db"},
+	{"password file over env", map[string]string{"APP_ENV": "staging", "DB_PASSWORD": "env_password"}, true,
+		"file_user", "secret_password", "staging_db"},
+}
+
+func TestNewConfigSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	passwordFile := writeConfigFile(t, dir, "db_password", "secret_password\n")
+
+	for _, test := range configSourceTests {
+		t.Run(test.name, func(t *testing.T) {
+			env := map[string]string{"APP_ENV": "", "DB_USER": "", "DB_PASSWORD": "", "DB_PASSWORD_FILE": "", "DB_NAME": ""}
+			for key, value := range test.env {
+				env[key] = value
+			}
+			if test.passwordFile {
+				env["DB_PASSWORD_FILE"] = passwordFile
+			}
+			setEnv(t, env)
+
+			conf, err := NewConfig("testdata/config.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			db := conf.DBConfig
+			if db.User != test.wantUser || db.Password != test.wantPassword || db.DbName != test.wantName {
+				t.Errorf("got user %q password %q name %q, want %q %q %q", db.User, db.Password, db.DbName,
+					test.wantUser, test.wantPassword, test.wantName)
+			}
+			wantLimit := 10
+			if test.env["APP_ENV"] == "staging" {
+				wantLimit = 20
+			}
+			if conf.Feed.Limit != wantLimit {
+				t.Errorf("feed.limit %d, want %d", conf.Feed.Limit, wantLimit)
+			}
+		})
+	}
+
+	setEnv(t, map[string]string{"APP_ENV": "production"})
+	if _, err = NewConfig("testdata/config.yaml"); err == nil {
+		t.Error("missing overlay of APP_ENV accepted")
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyFile := writeConfigFile(t, dir, "api_key", "file-key\r\n")
+	smtpFile := writeConfigFile(t, dir, "smtp_password", "smtp-file-password")
+
+	conf := validConfig()
+	conf.Auth.APIKeys = []APIKeyConfig{{Name: "inline", Key: "inline-key"}, {Name: "mounted", Key: "inline-key", KeyFile: keyFile}}
+	conf.Notification.SMTP.Password, conf.Notification.SMTP.PasswordFile = "inline-password", smtpFile
+	conf.Spam.FormSecret = "inline-secret"
+	if err = loadSecretFiles(reflect.ValueOf(conf).Elem(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Auth.APIKeys[0].Key != "inline-key" || conf.Auth.APIKeys[1].Key != "file-key" {
+		t.Errorf("api keys %+v", conf.Auth.APIKeys)
+	}
+	if conf.Notification.SMTP.Password != "smtp-file-password" {
+		t.Errorf("smtp password %q", conf.Notification.SMTP.Password)
+	}
+	if conf.Spam.FormSecret != "inline-secret" {
+		t.Errorf("form secret %q, kept without a file", conf.Spam.FormSecret)
+	}
+
+	conf.Auth.APIKeys[0].KeyFile = filepath.Join(dir, "missing")
+	err = loadSecretFiles(reflect.ValueOf(conf).Elem(), "")
+	if err == nil || !strings.HasPrefix(err.Error(), "auth.api_keys[0].key_file:") {
+		t.Errorf("got %v, want an error naming auth.api_keys[0].key_file", err)
+	}
+}
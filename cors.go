@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CORSConfig is config struct for cross origin requests from browsers
+type CORSConfig struct {
+	// origins allowed to call the api, such as https://www.codonex.com, * allows any
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+// Headers of cross origin requests
+const (
+	corsAllowMethods  = "GET, POST, PUT, DELETE"
+	corsAllowHeaders  = "Accept, Accept-Language, Authorization, Content-Type, If-Match, If-None-Match, X-API-Key, X-Request-ID"
+	corsExposeHeaders = "Content-Disposition, ETag, Retry-After, X-Request-ID"
+	corsMaxAge        = "600"
+)
+
+// corsPolicy answers cross origin requests from the allowed origins. The
+// origins change on config reload.
+type corsPolicy struct {
+	mutex   sync.RWMutex
+	origins map[string]bool
+	any     bool
+}
+
+func newCORSPolicy(conf CORSConfig) *corsPolicy {
+	policy := &corsPolicy{}
+	policy.set(conf)
+	return policy
+}
+
+// set replaces the allowed origins
+func (p *corsPolicy) set(conf CORSConfig) {
+	origins := map[string]bool{}
+	any := false
+	for _, origin := range conf.AllowedOrigins {
+		if origin == "*" {
+			any = true
+		}
+		origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.origins, p.any = origins, any
+}
+
+func (p *corsPolicy) allowed(origin string) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.any || p.origins[strings.ToLower(origin)]
+}
+
+// handler adds the CORS headers for allowed origins and answers their
+// preflight requests. It wraps the router since mux rejects OPTIONS requests
+// before its middlewares run.
+func (p *corsPolicy) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(writer, req)
+			return
+		}
+		header := writer.Header()
+		header.Add("Vary", "Origin")
+		if !p.allowed(origin) {
+			next.ServeHTTP(writer, req)
+			return
+		}
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			header.Set("Access-Control-Max-Age", corsMaxAge)
+			writer.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(writer, req)
+	})
+}
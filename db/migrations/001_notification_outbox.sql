@@ -0,0 +1,16 @@
+CREATE TABLE notification_outbox(
+    id serial NOT NULL,
+    recipients text NOT NULL,
+    subject text NOT NULL,
+    body text NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    next_attempt_at timestamp NOT NULL,
+    sent_at timestamp,
+    failed_at timestamp,
+    created timestamp,
+    CONSTRAINT notification_outbox_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE);
+
+CREATE INDEX notification_outbox_pending_idx ON notification_outbox (next_attempt_at)
+    WHERE sent_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE job_application_rejection(
+    id serial NOT NULL,
+    ip text NOT NULL,
+    email text,
+    reason text NOT NULL,
+    payload text,
+    created timestamp,
+    CONSTRAINT job_application_rejection_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE);
@@ -0,0 +1,11 @@
+ALTER TABLE news_item
+    ADD COLUMN status character varying(16) NOT NULL DEFAULT 'draft',
+    ADD COLUMN publish_at timestamp,
+    ADD COLUMN expire_at timestamp,
+    ADD COLUMN updated timestamp,
+    ADD CONSTRAINT news_item_status_check CHECK (status IN ('draft', 'published', 'archived'));
+
+-- items created before the workflow existed were already public
+UPDATE news_item SET status = 'published', publish_at = created;
+
+CREATE INDEX news_item_visible_idx ON news_item (status, publish_at, expire_at);
@@ -0,0 +1,21 @@
+ALTER TABLE news_item ADD COLUMN slug character varying(120);
+ALTER TABLE project ADD COLUMN slug character varying(120);
+
+-- existing rows get a transliterated slug, suffixed with the uid to keep it unique
+UPDATE news_item SET slug = trim(both '-' from regexp_replace(
+    lower(translate(news_title, 'ıİIşŞğĞüÜöÖçÇ', 'iiissgguuoocc')), '[^a-z0-9]+', '-', 'g')) || '-' || uid;
+UPDATE project SET slug = trim(both '-' from regexp_replace(
+    lower(translate(project_name, 'ıİIşŞğĞüÜöÖçÇ', 'iiissgguuoocc')), '[^a-z0-9]+', '-', 'g')) || '-' || uid;
+
+ALTER TABLE news_item ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE project ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX news_item_slug_idx ON news_item (slug);
+CREATE UNIQUE INDEX project_slug_idx ON project (slug);
+
+CREATE TABLE slug_redirect(
+    resource_type character varying(16) NOT NULL,
+    slug character varying(120) NOT NULL,
+    target_uid integer NOT NULL,
+    created timestamp,
+    CONSTRAINT slug_redirect_pkey PRIMARY KEY (resource_type, slug)
+) WITH (OIDS = FALSE);
@@ -0,0 +1,19 @@
+CREATE TABLE news_translation(
+    news_uid integer NOT NULL,
+    locale character varying(8) NOT NULL,
+    news_title character varying(100) NOT NULL,
+    detail text NOT NULL,
+    updated timestamp,
+    CONSTRAINT news_translation_pkey PRIMARY KEY (news_uid, locale),
+    FOREIGN KEY (news_uid) REFERENCES news_item (uid) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE TABLE project_translation(
+    project_uid integer NOT NULL,
+    locale character varying(8) NOT NULL,
+    project_name character varying(150) NOT NULL,
+    detail text NOT NULL,
+    updated timestamp,
+    CONSTRAINT project_translation_pkey PRIMARY KEY (project_uid, locale),
+    FOREIGN KEY (project_uid) REFERENCES project (uid) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
@@ -0,0 +1,36 @@
+CREATE TABLE tag(
+    id serial NOT NULL,
+    name character varying(64) NOT NULL,
+    slug character varying(80) NOT NULL,
+    created timestamp,
+    CONSTRAINT tag_pkey PRIMARY KEY (id),
+    CONSTRAINT tag_slug_key UNIQUE (slug)
+) WITH (OIDS = FALSE);
+
+CREATE TABLE category(
+    id serial NOT NULL,
+    name character varying(64) NOT NULL,
+    slug character varying(80) NOT NULL,
+    created timestamp,
+    CONSTRAINT category_pkey PRIMARY KEY (id),
+    CONSTRAINT category_slug_key UNIQUE (slug)
+) WITH (OIDS = FALSE);
+
+CREATE TABLE news_tag(
+    news_uid integer NOT NULL,
+    tag_id integer NOT NULL,
+    CONSTRAINT news_tag_pkey PRIMARY KEY (news_uid, tag_id),
+    FOREIGN KEY (news_uid) REFERENCES news_item (uid) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tag (id) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE TABLE news_category(
+    news_uid integer NOT NULL,
+    category_id integer NOT NULL,
+    CONSTRAINT news_category_pkey PRIMARY KEY (news_uid, category_id),
+    FOREIGN KEY (news_uid) REFERENCES news_item (uid) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES category (id) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE INDEX news_tag_tag_idx ON news_tag (tag_id);
+CREATE INDEX news_category_category_idx ON news_category (category_id);
@@ -0,0 +1,11 @@
+ALTER TABLE news_item
+    ADD COLUMN detail_format character varying(16) NOT NULL DEFAULT 'text',
+    ADD COLUMN detail_html text NOT NULL DEFAULT '';
+
+ALTER TABLE news_translation
+    ADD COLUMN detail_format character varying(16) NOT NULL DEFAULT 'text',
+    ADD COLUMN detail_html text NOT NULL DEFAULT '';
+
+-- existing details are plain text, render them as escaped paragraphs
+UPDATE news_item SET detail_html = '<p>' || replace(replace(replace(detail, '&', '&amp;'), '<', '&lt;'), '>', '&gt;') || '</p>';
+UPDATE news_translation SET detail_html = '<p>' || replace(replace(replace(detail, '&', '&amp;'), '<', '&lt;'), '>', '&gt;') || '</p>';
@@ -0,0 +1,56 @@
+CREATE TABLE client(
+    id serial NOT NULL,
+    name character varying(150) NOT NULL,
+    slug character varying(80) NOT NULL,
+    website text,
+    created timestamp,
+    CONSTRAINT client_pkey PRIMARY KEY (id),
+    CONSTRAINT client_slug_key UNIQUE (slug)
+) WITH (OIDS = FALSE);
+
+ALTER TABLE project ADD COLUMN client_id integer REFERENCES client (id) ON DELETE SET NULL;
+CREATE INDEX project_client_idx ON project (client_id);
+
+CREATE TABLE project_location(
+    project_uid integer NOT NULL,
+    name character varying(150),
+    city character varying(100),
+    country character varying(100),
+    latitude double precision,
+    longitude double precision,
+    CONSTRAINT project_location_pkey PRIMARY KEY (project_uid),
+    CONSTRAINT project_location_coordinates_check CHECK (
+        (latitude IS NULL AND longitude IS NULL) OR
+        (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)),
+    FOREIGN KEY (project_uid) REFERENCES project (uid) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE TABLE technology(
+    id serial NOT NULL,
+    name character varying(64) NOT NULL,
+    slug character varying(80) NOT NULL,
+    created timestamp,
+    CONSTRAINT technology_pkey PRIMARY KEY (id),
+    CONSTRAINT technology_slug_key UNIQUE (slug)
+) WITH (OIDS = FALSE);
+
+CREATE TABLE project_technology(
+    project_uid integer NOT NULL,
+    technology_id integer NOT NULL,
+    CONSTRAINT project_technology_pkey PRIMARY KEY (project_uid, technology_id),
+    FOREIGN KEY (project_uid) REFERENCES project (uid) ON DELETE CASCADE,
+    FOREIGN KEY (technology_id) REFERENCES technology (id) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE TABLE project_member(
+    id serial NOT NULL,
+    project_uid integer NOT NULL,
+    name character varying(150) NOT NULL,
+    role character varying(100) NOT NULL,
+    position integer NOT NULL,
+    CONSTRAINT project_member_pkey PRIMARY KEY (id),
+    FOREIGN KEY (project_uid) REFERENCES project (uid) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE INDEX project_technology_technology_idx ON project_technology (technology_id);
+CREATE INDEX project_member_project_idx ON project_member (project_uid, position);
@@ -0,0 +1,10 @@
+CREATE TABLE news_project(
+    news_uid integer NOT NULL,
+    project_uid integer NOT NULL,
+    created timestamp,
+    CONSTRAINT news_project_pkey PRIMARY KEY (news_uid, project_uid),
+    FOREIGN KEY (news_uid) REFERENCES news_item (uid) ON DELETE CASCADE,
+    FOREIGN KEY (project_uid) REFERENCES project (uid) ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE INDEX news_project_project_idx ON news_project (project_uid);
@@ -0,0 +1,13 @@
+ALTER TABLE news_item
+    ADD COLUMN deleted_at timestamp,
+    ADD COLUMN deleted_by character varying(64);
+ALTER TABLE project
+    ADD COLUMN deleted_at timestamp,
+    ADD COLUMN deleted_by character varying(64);
+ALTER TABLE job_application
+    ADD COLUMN deleted_at timestamp,
+    ADD COLUMN deleted_by character varying(64);
+
+CREATE INDEX news_item_deleted_idx ON news_item (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX project_deleted_idx ON project (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX job_application_deleted_idx ON job_application (deleted_at) WHERE deleted_at IS NOT NULL;
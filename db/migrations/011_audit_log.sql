@@ -0,0 +1,17 @@
+CREATE TABLE audit_log(
+    id bigserial NOT NULL,
+    actor character varying(64) NOT NULL,
+    action character varying(16) NOT NULL,
+    resource_type character varying(32) NOT NULL,
+    resource_id character varying(64) NOT NULL,
+    before jsonb,
+    after jsonb,
+    request_id character varying(64),
+    created timestamp NOT NULL,
+    CONSTRAINT audit_log_pkey PRIMARY KEY (id)
+) WITH (OIDS = FALSE);
+
+CREATE INDEX audit_log_resource_idx ON audit_log (resource_type, resource_id, created);
+CREATE INDEX audit_log_actor_idx ON audit_log (actor, created);
+CREATE INDEX audit_log_request_idx ON audit_log (request_id);
+CREATE INDEX audit_log_created_idx ON audit_log (created);
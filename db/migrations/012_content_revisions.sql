@@ -0,0 +1,18 @@
+CREATE TABLE content_revision(
+    id bigserial NOT NULL,
+    resource_type character varying(16) NOT NULL,
+    resource_uid integer NOT NULL,
+    revision integer NOT NULL,
+    data jsonb NOT NULL,
+    actor character varying(64) NOT NULL,
+    request_id character varying(64),
+    created timestamp NOT NULL,
+    CONSTRAINT content_revision_pkey PRIMARY KEY (id),
+    CONSTRAINT content_revision_key UNIQUE (resource_type, resource_uid, revision)
+) WITH (OIDS = FALSE);
+
+-- the current state of existing rows becomes their first revision
+INSERT INTO content_revision(resource_type,resource_uid,revision,data,actor,created)
+    SELECT 'news', n.uid, 1, to_jsonb(n), 'system', now() FROM news_item n;
+INSERT INTO content_revision(resource_type,resource_uid,revision,data,actor,created)
+    SELECT 'project', p.uid, 1, to_jsonb(p), 'system', now() FROM project p;
@@ -0,0 +1,3 @@
+ALTER TABLE news_item ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE project ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE job_application ADD COLUMN version integer NOT NULL DEFAULT 1;
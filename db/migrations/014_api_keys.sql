@@ -0,0 +1,11 @@
+CREATE TABLE api_key(
+    id serial NOT NULL,
+    name character varying(64) NOT NULL,
+    role character varying(16) NOT NULL,
+    key_hash character(64) NOT NULL,
+    created timestamp NOT NULL,
+    revoked_at timestamp,
+    CONSTRAINT api_key_pkey PRIMARY KEY (id),
+    CONSTRAINT api_key_name_key UNIQUE (name),
+    CONSTRAINT api_key_hash_key UNIQUE (key_hash)
+) WITH (OIDS = FALSE);
@@ -0,0 +1,7 @@
+-- publish_at and expire_at are compared with now(), store them with their
+-- time zone so client offsets are kept and scheduling does not depend on the
+-- TimeZone setting. Existing values are read in the session time zone, the
+-- one the comparisons used so far.
+ALTER TABLE news_item
+    ALTER COLUMN publish_at TYPE timestamptz,
+    ALTER COLUMN expire_at TYPE timestamptz;
@@ -0,0 +1,15 @@
+-- 004 gave items whose title has no letters or digits slugs like -12. Such
+-- items get the item fallback of Slugify, the old slug keeps redirecting.
+INSERT INTO slug_redirect(resource_type, slug, target_uid, created)
+    SELECT 'news', slug, uid, now() FROM news_item n
+    WHERE slug ~ '^-[0-9]+$' AND NOT EXISTS (SELECT 1 FROM news_item o WHERE o.slug = 'item' || n.slug)
+    ON CONFLICT DO NOTHING;
+UPDATE news_item n SET slug = 'item' || n.slug
+    WHERE n.slug ~ '^-[0-9]+$' AND NOT EXISTS (SELECT 1 FROM news_item o WHERE o.slug = 'item' || n.slug);
+
+INSERT INTO slug_redirect(resource_type, slug, target_uid, created)
+    SELECT 'project', slug, uid, now() FROM project p
+    WHERE slug ~ '^-[0-9]+$' AND NOT EXISTS (SELECT 1 FROM project o WHERE o.slug = 'item' || p.slug)
+    ON CONFLICT DO NOTHING;
+UPDATE project p SET slug = 'item' || p.slug
+    WHERE p.slug ~ '^-[0-9]+$' AND NOT EXISTS (SELECT 1 FROM project o WHERE o.slug = 'item' || p.slug);
@@ -0,0 +1,10 @@
+-- Projects saved without a start or finish date stored the zero date
+-- 0001-01-01 instead of NULL, which status filters and the timeline treat as a
+-- real date. Revisions are fixed as well so a revert does not bring it back.
+UPDATE project SET start_date = NULL WHERE start_date = '0001-01-01';
+UPDATE project SET finish_date = NULL WHERE finish_date = '0001-01-01';
+
+UPDATE content_revision SET data = jsonb_set(data, '{start_date}', 'null')
+    WHERE resource_type = 'project' AND data->>'start_date' = '0001-01-01';
+UPDATE content_revision SET data = jsonb_set(data, '{finish_date}', 'null')
+    WHERE resource_type = 'project' AND data->>'finish_date' = '0001-01-01';
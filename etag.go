@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// versionETag returns the entity tag of an item version. Localized
+// representations carry the locale since they differ per language.
+func versionETag(version int, locale string) string {
+	if locale == "" {
+		return `"` + strconv.Itoa(version) + `"`
+	}
+	return `"` + strconv.Itoa(version) + "-" + locale + `"`
+}
+
+// etagVersion returns the item version of an entity tag made by versionETag
+func etagVersion(etag string) (int, bool) {
+	etag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(etag), "W/"), `"`)
+	if i := strings.Index(etag, "-"); i >= 0 {
+		etag = etag[:i]
+	}
+	version, err := strconv.Atoi(etag)
+	return version, err == nil
+}
+
+// etagMatches reports whether header, a list of entity tags, contains etag
+// using the weak comparison of RFC 7232
+func etagMatches(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// renderVersioned renders a single item with its entity tag, answering
+// 304 Not Modified when the client sent a matching If-None-Match
+func (app *App) renderVersioned(writer http.ResponseWriter, req *http.Request, etag string, data interface{}) {
+	writer.Header().Set("ETag", etag)
+	if match := req.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+	app.RenderJson(writer, http.StatusOK, data)
+}
+
+// versionConflict is the error of a failed If-Match, it carries the current
+// version of the item
+type versionConflict struct {
+	version int
+}
+
+func (e versionConflict) Error() string {
+	return "version mismatch"
+}
+
+// matchVersion compares match, an If-Match header value, with the current
+// version of an item. It returns 412 Precondition Failed on a mismatch, and
+// 428 Precondition Required when match is required but empty.
+func matchVersion(match string, version int, required bool) *ErrorResponse {
+	if match == "" {
+		if required {
+			return &ErrorResponse{Status: http.StatusPreconditionRequired, Error: errors.New("missing If-Match header"),
+				Message: "Send the ETag of the item in If-Match"}
+		}
+		return nil
+	}
+	for _, candidate := range strings.Split(match, ",") {
+		if strings.TrimSpace(candidate) == "*" {
+			return nil
+		}
+		if candidateVersion, ok := etagVersion(candidate); ok && candidateVersion == version {
+			return nil
+		}
+	}
+	return &ErrorResponse{Status: http.StatusPreconditionFailed, Error: versionConflict{version: version},
+		Message: "Item was changed by someone else"}
+}
+
+// checkIfMatch compares If-Match with the current version of an item and
+// renders the error response of matchVersion
+func (app *App) checkIfMatch(writer http.ResponseWriter, req *http.Request, version int, required bool) bool {
+	if ex := matchVersion(req.Header.Get("If-Match"), version, required); ex != nil {
+		app.renderVersionedError(writer, *ex)
+		return false
+	}
+	return true
+}
+
+// renderVersionedError renders ex, with the ETag of the current version when
+// it is a version conflict
+func (app *App) renderVersionedError(writer http.ResponseWriter, ex ErrorResponse) {
+	if conflict, ok := ex.Error.(versionConflict); ok {
+		writer.Header().Set("ETag", versionETag(conflict.version, ""))
+	}
+	app.RenderError(writer, ex)
+}
+
+// bumpVersion increments the version of an item whose representation changed
+// through a related table. It writes no revision since the item row is unchanged.
+func bumpVersion(db dbExecutor, table string, id interface{}) error {
+	_, err := db.Exec("UPDATE "+table+" SET version=version+1 WHERE uid=$1", id)
+	return err
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionETag(t *testing.T) {
+	if got := versionETag(7, ""); got != `"7"` {
+		t.Errorf("versionETag(7) = %s", got)
+	}
+	if got := versionETag(7, "en"); got != `"7-en"` {
+		t.Errorf("versionETag(7, en) = %s", got)
+	}
+}
+
+func TestETagVersion(t *testing.T) {
+	tests := []struct {
+		etag   string
+		want   int
+		wantOK bool
+	}{
+		{`"7"`, 7, true},
+		{` "7" `, 7, true},
+		{`W/"7"`, 7, true},
+		{`"7-en"`, 7, true},
+		{`W/"12-tr"`, 12, true},
+		// unquoted tags are accepted, some clients send the bare version
+		{`7`, 7, true},
+		{`*`, 0, false},
+		{`""`, 0, false},
+		{`"abc"`, 0, false},
+		{`"-en"`, 0, false},
+		{`w/"7"`, 0, false},
+	}
+	for _, test := range tests {
+		got, ok := etagVersion(test.etag)
+		if ok != test.wantOK || (ok && got != test.want) {
+			t.Errorf("etagVersion(%s) = %d, %v, want %d, %v", test.etag, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		want   bool
+	}{
+		{`"7"`, `"7"`, true},
+		{`W/"7"`, `"7"`, true},
+		{`"7"`, `W/"7"`, true},
+		{`"6", "7"`, `"7"`, true},
+		{`*`, `"7"`, true},
+		{`"6"`, `"7"`, false},
+		{`"7-en"`, `"7"`, false},
+		{`7`, `"7"`, false},
+		{``, `"7"`, false},
+	}
+	for _, test := range tests {
+		if got := etagMatches(test.header, test.etag); got != test.want {
+			t.Errorf("etagMatches(%s, %s) = %v, want %v", test.header, test.etag, got, test.want)
+		}
+	}
+}
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		match    string
+		required bool
+		want     int
+	}{
+		{"strong tag", `"3"`, true, 0},
+		{"weak tag", `W/"3"`, true, 0},
+		{"localized tag", `"3-en"`, true, 0},
+		{"wildcard", `*`, true, 0},
+		{"list with current version", `"1", W/"3"`, true, 0},
+		{"list with wildcard", `"1", *`, true, 0},
+		{"stale version", `"2"`, true, http.StatusPreconditionFailed},
+		{"stale list", `"1", "2"`, true, http.StatusPreconditionFailed},
+		{"malformed tag", `"abc"`, true, http.StatusPreconditionFailed},
+		{"empty list items", `,,`, true, http.StatusPreconditionFailed},
+		{"missing and required", ``, true, http.StatusPreconditionRequired},
+		{"missing and optional", ``, false, 0},
+		{"stale and optional", `"2"`, false, http.StatusPreconditionFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ex := matchVersion(test.match, 3, test.required)
+			if test.want == 0 {
+				if ex != nil {
+					t.Errorf("got %d %s", ex.Status, ex.Message)
+				}
+				return
+			}
+			if ex == nil || ex.Status != test.want {
+				t.Fatalf("got %+v, want %d", ex, test.want)
+			}
+			if conflict, ok := ex.Error.(versionConflict); test.want == http.StatusPreconditionFailed && (!ok || conflict.version != 3) {
+				t.Errorf("error %v does not carry the current version", ex.Error)
+			}
+		})
+	}
+}
+
+func TestRenderVersionedError(t *testing.T) {
+	app := &App{}
+	recorder := httptest.NewRecorder()
+	app.renderVersionedError(recorder, *matchVersion(`"2"`, 3, true))
+	if recorder.Code != http.StatusPreconditionFailed || recorder.Header().Get("ETag") != `"3"` {
+		t.Errorf("412: got %d with ETag %q", recorder.Code, recorder.Header().Get("ETag"))
+	}
+
+	recorder = httptest.NewRecorder()
+	app.renderVersionedError(recorder, *matchVersion("", 3, true))
+	if recorder.Code != http.StatusPreconditionRequired || recorder.Header().Get("ETag") != "" {
+		t.Errorf("428: got %d with ETag %q", recorder.Code, recorder.Header().Get("ETag"))
+	}
+}
+
+func TestRenderVersioned(t *testing.T) {
+	app := &App{}
+	tests := []struct {
+		ifNoneMatch string
+		want        int
+	}{
+		{"", http.StatusOK},
+		{`"3"`, http.StatusNotModified},
+		{`W/"3"`, http.StatusNotModified},
+		{`"2"`, http.StatusOK},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/news/1", nil)
+		if test.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", test.ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		app.renderVersioned(recorder, req, `"3"`, map[string]int{"version": 3})
+		if recorder.Code != test.want || recorder.Header().Get("ETag") != `"3"` {
+			t.Errorf("If-None-Match %q: got %d with ETag %q, want %d", test.ifNoneMatch, recorder.Code, recorder.Header().Get("ETag"), test.want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+// FeedConfig is config struct for the news feeds
+type FeedConfig struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	// public website, news links point to <site_url>/news/<slug>
+	SiteURL string `yaml:"site_url" envconfig:"FEED_SITE_URL"`
+	// public url of this api, used for image enclosures
+	APIURL string `yaml:"api_url" envconfig:"FEED_API_URL"`
+	// maximum number of items in a feed
+	Limit int `yaml:"limit"`
+}
+
+// feedItem is a published news item as it appears in a feed
+type feedItem struct {
+	ID         int
+	Slug       string
+	Title      string
+	DetailHTML string
+	Published  time.Time
+	Updated    time.Time
+	ImageSize  int
+	ImageStart []byte
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string      `xml:"xml:lang,attr"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	ID        string     `xml:"id"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   string     `xml:"summary"`
+	Links     []atomLink `xml:"link"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int    `xml:"length,attr,omitempty"`
+}
+
+// excerpt shortens text to at most max runes, cutting at a word boundary
+func excerpt(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)[:max]
+	cut := string(runes)
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
+
+// newsLink returns the public website url of a news item
+func (app *App) newsLink(item feedItem) string {
+	return strings.TrimRight(app.conf.Feed.SiteURL, "/") + "/news/" + item.Slug
+}
+
+// newsImageURL returns the url the image of a news item is served from
+func (app *App) newsImageURL(item feedItem) string {
+	return strings.TrimRight(app.conf.Feed.APIURL, "/") + "/news/" + strconv.Itoa(item.ID) + "/image"
+}
+
+// feedItems loads the currently published news items for a feed
+func (app *App) feedItems(locale string) ([]feedItem, error) {
+	limit := app.conf.Feed.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	var where whereBuilder
+	from := newsFrom(&where, locale)
+	where.Add(newsVisibleSQL)
+	sql := "SELECT n.uid,n.slug,COALESCE(t.news_title,n.news_title),COALESCE(t.detail_html,n.detail_html)," +
+		"COALESCE(n.publish_at,n.created),GREATEST(n.updated,t.updated,n.publish_at,n.created)," +
+		"COALESCE(octet_length(n.news_image),0),substring(n.news_image from 1 for 512) FROM " + from + where.SQL() +
+		" ORDER BY COALESCE(n.publish_at,n.created) DESC LIMIT " + where.Arg(limit)
+	rows, err := app.db.Query(sql, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []feedItem
+	for rows.Next() {
+		item := feedItem{}
+		err = rows.Scan(&item.ID, &item.Slug, &item.Title, &item.DetailHTML, &item.Published, &item.Updated, &item.ImageSize, &item.ImageStart)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// lastModified returns the latest change of the feed items
+func lastModified(items []feedItem) time.Time {
+	var latest time.Time
+	for _, item := range items {
+		if item.Updated.After(latest) {
+			latest = item.Updated
+		}
+	}
+	return latest
+}
+
+// GetNewsRSS renders the published news as an RSS 2.0 feed
+func (app *App) GetNewsRSS(writer http.ResponseWriter, req *http.Request) {
+	locale := app.requestLocale(req)
+	items, err := app.feedItems(locale)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get news feed")
+		return
+	}
+
+	updated := lastModified(items)
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed := rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         app.conf.Feed.Title,
+			Link:          app.conf.Feed.SiteURL,
+			Description:   app.conf.Feed.Description,
+			Language:      locale,
+			LastBuildDate: updated.UTC().Format(time.RFC1123Z),
+			Self:          rssLink{Href: strings.TrimRight(app.conf.Feed.APIURL, "/") + "/news/feed.rss", Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	for _, item := range items {
+		rss := rssItem{
+			Title:       item.Title,
+			Link:        app.newsLink(item),
+			Description: excerpt(PlainText(item.DetailHTML), newsExcerptLength),
+			GUID:        rssGUID{Value: app.newsLink(item), IsPermaLink: true},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		}
+		if item.ImageSize > 0 {
+			rss.Enclosure = &rssEnclosure{URL: app.newsImageURL(item), Length: item.ImageSize, Type: http.DetectContentType(item.ImageStart)}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rss)
+	}
+	app.renderFeed(writer, req, "application/rss+xml; charset=utf-8", locale, updated, feed)
+}
+
+// GetNewsAtom renders the published news as an Atom feed
+func (app *App) GetNewsAtom(writer http.ResponseWriter, req *http.Request) {
+	locale := app.requestLocale(req)
+	items, err := app.feedItems(locale)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get news feed")
+		return
+	}
+
+	updated := lastModified(items)
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed := atomFeed{
+		Lang:    locale,
+		Title:   app.conf.Feed.Title,
+		ID:      strings.TrimRight(app.conf.Feed.SiteURL, "/") + "/news",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: app.conf.Feed.SiteURL, Rel: "alternate", Type: "text/html"},
+			{Href: strings.TrimRight(app.conf.Feed.APIURL, "/") + "/news/feed.atom", Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	for _, item := range items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        app.newsLink(item),
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Summary:   excerpt(PlainText(item.DetailHTML), newsExcerptLength),
+			Links:     []atomLink{{Href: app.newsLink(item), Rel: "alternate", Type: "text/html"}},
+		}
+		if item.ImageSize > 0 {
+			entry.Links = append(entry.Links, atomLink{Href: app.newsImageURL(item), Rel: "enclosure",
+				Type: http.DetectContentType(item.ImageStart), Length: item.ImageSize})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	app.renderFeed(writer, req, "application/atom+xml; charset=utf-8", locale, updated, feed)
+}
+
+// renderFeed writes a feed, answering 304 Not Modified when the client
+// already has the current version
+func (app *App) renderFeed(writer http.ResponseWriter, req *http.Request, contentType string, locale string, updated time.Time, feed interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(feed); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to render news feed")
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	header := writer.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "public, max-age=300")
+	setContentLanguage(writer, locale)
+	header.Set("Last-Modified", updated.UTC().Format(http.TimeFormat))
+
+	if notModified(req, etag) {
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", contentType)
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(buf.Bytes())
+}
+
+// notModified evaluates If-None-Match. If-Modified-Since is not used: the
+// latest change of the remaining items does not move when an item is
+// unpublished, expires or is trashed, the ETag of the feed body does.
+func notModified(req *http.Request, etag string) bool {
+	match := req.Header.Get("If-None-Match")
+	return match != "" && etagMatches(match, etag)
+}
+
+// GetNewsImage serves the image of a news item
+func (app *App) GetNewsImage(writer http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	var where whereBuilder
+	where.Add("n.uid=?", params["id"])
+	if app.authenticate(req).IsEditor() {
+		where.Add(newsLiveSQL)
+	} else {
+		where.Add(newsVisibleSQL)
+	}
+
+	var image []byte
+	var public bool
+	sql := "SELECT n.news_image,(" + newsVisibleSQL + ") FROM news_item n" + where.SQL()
+	err := app.db.QueryRow(sql, where.Args()...).Scan(&image, &public)
+	if err != nil || len(image) == 0 {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Image of news item [%s] not found", params["id"]))
+		return
+	}
+
+	writer.Header().Set("Content-Type", http.DetectContentType(image))
+	writer.Header().Set("Content-Length", strconv.Itoa(len(image)))
+	writer.Header().Set("Cache-Control", imageCacheControl(public))
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(image)
+}
+
+// imageCacheControl returns the Cache-Control of a news image. Images of items
+// the public may not see are kept out of shared caches, which could otherwise
+// go on serving them after the item is unpublished.
+func imageCacheControl(public bool) string {
+	if public {
+		return "public, max-age=3600"
+	}
+	return "private, no-store"
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"  spaces\n and\tlines ", 20, "spaces and lines"},
+		{"cut at a word boundary", 12, "cut at a…"},
+		{"Çağrı merkezi açıldı", 8, "Çağrı…"},
+		{"oneverylongword", 4, "onev…"},
+	}
+	for _, test := range tests {
+		if got := excerpt(test.text, test.max); got != test.want {
+			t.Errorf("excerpt(%q, %d) = %q, want %q", test.text, test.max, got, test.want)
+		}
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	older := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	if got := lastModified(nil); !got.IsZero() {
+		t.Errorf("lastModified(nil) = %v, want zero", got)
+	}
+	if got := lastModified([]feedItem{{Updated: older}, {Updated: newer}, {Updated: older}}); !got.Equal(newer) {
+		t.Errorf("lastModified = %v, want %v", got, newer)
+	}
+}
+
+func TestRenderFeed(t *testing.T) {
+	app := &App{conf: &Config{}}
+	updated := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	feed := atomFeed{Title: "News", Entries: []atomEntry{{Title: "First"}, {Title: "Second"}}}
+	render := func(feed atomFeed, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/news/feed.atom", nil)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		recorder := httptest.NewRecorder()
+		app.renderFeed(recorder, req, "application/atom+xml; charset=utf-8", "tr", updated, feed)
+		return recorder
+	}
+
+	first := render(feed, nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || !strings.Contains(first.Body.String(), "<title>Second</title>") {
+		t.Fatalf("got %d etag %q body %s", first.Code, etag, first.Body)
+	}
+	if got := first.Header().Get("Last-Modified"); got != "Tue, 10 Mar 2026 08:00:00 GMT" {
+		t.Errorf("Last-Modified %q", got)
+	}
+
+	// An item leaving the feed does not move the latest change of the others
+	removed := feed
+	removed.Entries = feed.Entries[:1]
+	since := updated.Format(http.TimeFormat)
+	tests := []struct {
+		name   string
+		feed   atomFeed
+		header map[string]string
+		want   int
+	}{
+		{"same etag", feed, map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in a list", feed, map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"item removed", removed, map[string]string{"If-None-Match": etag}, http.StatusOK},
+		{"if-modified-since ignored", removed, map[string]string{"If-Modified-Since": since}, http.StatusOK},
+		{"if-none-match takes precedence", feed, map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": since}, http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := render(test.feed, test.header)
+			if got.Code != test.want {
+				t.Errorf("got %d, want %d", got.Code, test.want)
+			}
+			if test.want == http.StatusNotModified && got.Body.Len() != 0 {
+				t.Errorf("304 with body %s", got.Body)
+			}
+		})
+	}
+}
+
+func TestImageCacheControl(t *testing.T) {
+	if got := imageCacheControl(true); got != "public, max-age=3600" {
+		t.Errorf("public image: %q", got)
+	}
+	if got := imageCacheControl(false); got != "private, no-store" {
+		t.Errorf("hidden image: %q", got)
+	}
+}
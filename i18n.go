@@ -0,0 +1,419 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ContentConfig is config struct for published content
+type ContentConfig struct {
+	// language of the content stored on news_item and project rows
+	DefaultLanguage string `yaml:"default_language" envconfig:"DEFAULT_LANGUAGE"`
+	// languages translations may be added for
+	Languages []string `yaml:"languages"`
+}
+
+// NewsTranslationRequest request struct
+//
+//swagger:model NewsTranslationRequest
+type NewsTranslationRequest struct {
+	Title        string `json:"title"`
+	Detail       string `json:"detail"`
+	DetailFormat string `json:"detail_format,omitempty"`
+}
+
+// ProjectTranslationRequest request struct
+//
+//swagger:model ProjectTranslationRequest
+type ProjectTranslationRequest struct {
+	ProjectName string `json:"project_name"`
+	Detail      string `json:"detail"`
+}
+
+// TranslationResponse response struct
+//
+//swagger:response TranslationResponse
+type TranslationResponse struct {
+	Locale       string    `json:"locale"`
+	Title        string    `json:"title"`
+	Detail       string    `json:"detail"`
+	DetailFormat string    `json:"detail_format,omitempty"`
+	DetailHTML   string    `json:"detail_html,omitempty"`
+	Updated      time.Time `json:"updated"`
+}
+
+// translatable describes where the translations of a resource are stored
+type translatable struct {
+	table       string
+	parentTable string
+	foreignKey  string
+	titleColumn string
+	// the detail is rich text with a sanitized html rendering
+	richText bool
+	// resource type of the translations in the audit log
+	auditType string
+	// alias of the parent table in liveSQL and visibleSQL, which match the
+	// parents editors and the public may see
+	parentAlias string
+	liveSQL     string
+	visibleSQL  string
+}
+
+var (
+	newsTranslations = translatable{"news_translation", "news_item", "news_uid", "news_title", true, AuditNewsTranslation,
+		"n", newsLiveSQL, newsVisibleSQL}
+	projectTranslations = translatable{"project_translation", "project", "project_uid", "project_name", false, AuditProjectTranslation,
+		"p", projectLiveSQL, projectLiveSQL}
+)
+
+// columns returns the selected translation columns, matching scan
+func (t translatable) columns() string {
+	columns := "locale," + t.titleColumn + ",detail,updated"
+	if t.richText {
+		columns += ",detail_format,detail_html"
+	}
+	return columns
+}
+
+// scan reads a translation row selected with columns
+func (t translatable) scan(row rowScanner) (TranslationResponse, error) {
+	response := TranslationResponse{}
+	dest := []interface{}{&response.Locale, &response.Title, &response.Detail, &response.Updated}
+	if t.richText {
+		dest = append(dest, &response.DetailFormat, &response.DetailHTML)
+	}
+	return response, row.Scan(dest...)
+}
+
+// defaultLanguage returns the language of the untranslated content
+func (app *App) defaultLanguage() string {
+	if app.conf.Content.DefaultLanguage == "" {
+		return "tr"
+	}
+	return app.conf.Content.DefaultLanguage
+}
+
+// isSupportedLanguage reports whether locale is a configured language
+func (app *App) isSupportedLanguage(locale string) bool {
+	if locale == app.defaultLanguage() {
+		return true
+	}
+	for _, language := range app.conf.Content.Languages {
+		if language == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLocale turns "en-US" or "EN_us" into "en"
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// requestLocale picks the content language from ?lang=, then Accept-Language,
+// falling back to the default language
+func (app *App) requestLocale(req *http.Request) string {
+	if lang := normalizeLocale(req.URL.Query().Get("lang")); lang != "" && app.isSupportedLanguage(lang) {
+		return lang
+	}
+
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+	var accepted []weighted
+	for _, part := range strings.Split(req.Header.Get("Accept-Language"), ",") {
+		fields := strings.Split(part, ";")
+		locale := normalizeLocale(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if q := strings.TrimSpace(param); strings.HasPrefix(q, "q=") {
+				if value, err := strconv.ParseFloat(q[2:], 64); err == nil {
+					quality = value
+				}
+			}
+		}
+		accepted = append(accepted, weighted{locale, quality})
+	}
+	sort.SliceStable(accepted, func(i, j int) bool { return accepted[i].quality > accepted[j].quality })
+
+	for _, candidate := range accepted {
+		if candidate.quality > 0 && app.isSupportedLanguage(candidate.locale) {
+			return candidate.locale
+		}
+	}
+	return app.defaultLanguage()
+}
+
+// setContentLanguage sets the response headers of a localized response
+func setContentLanguage(writer http.ResponseWriter, locale string) {
+	writer.Header().Set("Content-Language", locale)
+	writer.Header().Add("Vary", "Accept-Language")
+}
+
+// resolvedLocale returns the locale a row was served in, translated holds the
+// locale of the joined translation, if any
+func (app *App) resolvedLocale(translated dbsql.NullString) string {
+	if translated.Valid {
+		return translated.String
+	}
+	return app.defaultLanguage()
+}
+
+// GetNewsTranslations lists the translations of a news item
+func (app *App) GetNewsTranslations(writer http.ResponseWriter, req *http.Request) {
+	app.listTranslations(writer, req, newsTranslations)
+}
+
+// PutNewsTranslation adds or updates the translation of a news item
+func (app *App) PutNewsTranslation(writer http.ResponseWriter, req *http.Request) {
+	var request NewsTranslationRequest
+	if !app.readTranslationRequest(writer, req, &request) {
+		return
+	}
+	app.saveTranslation(writer, req, newsTranslations, request.Title, request.Detail, request.DetailFormat)
+}
+
+// DeleteNewsTranslation deletes the translation of a news item
+func (app *App) DeleteNewsTranslation(writer http.ResponseWriter, req *http.Request) {
+	app.deleteTranslation(writer, req, newsTranslations)
+}
+
+// GetProjectTranslations lists the translations of a project item
+func (app *App) GetProjectTranslations(writer http.ResponseWriter, req *http.Request) {
+	app.listTranslations(writer, req, projectTranslations)
+}
+
+// PutProjectTranslation adds or updates the translation of a project item
+func (app *App) PutProjectTranslation(writer http.ResponseWriter, req *http.Request) {
+	var request ProjectTranslationRequest
+	if !app.readTranslationRequest(writer, req, &request) {
+		return
+	}
+	app.saveTranslation(writer, req, projectTranslations, request.ProjectName, request.Detail, FormatText)
+}
+
+// DeleteProjectTranslation deletes the translation of a project item
+func (app *App) DeleteProjectTranslation(writer http.ResponseWriter, req *http.Request) {
+	app.deleteTranslation(writer, req, projectTranslations)
+}
+
+// readTranslationRequest checks permissions and the locale, then decodes the body
+func (app *App) readTranslationRequest(writer http.ResponseWriter, req *http.Request, request interface{}) bool {
+	if !app.requireEditor(writer, req) {
+		return false
+	}
+	locale := mux.Vars(req)["locale"]
+	if locale != normalizeLocale(locale) || !app.isSupportedLanguage(locale) {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, fmt.Errorf("unsupported locale %q", locale), "Locale is wrong")
+		return false
+	}
+	if locale == app.defaultLanguage() {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, fmt.Errorf("%q is the default language", locale),
+			"Default language content is updated on the item itself")
+		return false
+	}
+
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
+		return false
+	}
+	if err = unmarshalRequest(req, reqBody, request); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to convert json code")
+		return false
+	}
+	return true
+}
+
+// saveTranslation upserts a translation of an item whose version matches If-Match
+func (app *App) saveTranslation(writer http.ResponseWriter, req *http.Request, t translatable, title string, detail string, format string) {
+	params := mux.Vars(req)
+	if title == "" || detail == "" {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, fmt.Errorf("title and detail are required"), "Title and detail not null")
+		return
+	}
+	if format == "" {
+		format = FormatText
+	}
+	detailHTML, err := RenderDetail(detail, format)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Detail format must be text, markdown or html")
+		return
+	}
+
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to save translation")
+		return
+	}
+	defer tx.Rollback()
+
+	version, ex := lockTranslationParent(tx, t, params["id"], req.Header.Get("If-Match"))
+	if ex != nil {
+		app.renderVersionedError(writer, *ex)
+		return
+	}
+
+	var before interface{}
+	current, err := t.scan(tx.QueryRow("SELECT "+t.columns()+" FROM "+t.table+" WHERE "+t.foreignKey+"=$1 AND locale=$2",
+		params["id"], params["locale"]))
+	if err == nil {
+		before = current
+	} else if err != dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to save translation")
+		return
+	}
+
+	columns := []string{t.foreignKey, "locale", t.titleColumn, "detail"}
+	args := []interface{}{params["id"], params["locale"], title, detail}
+	if t.richText {
+		columns = append(columns, "detail_format", "detail_html")
+		args = append(args, format, detailHTML)
+	}
+	placeholders := make([]string, len(columns))
+	updates := []string{"updated=EXCLUDED.updated"}
+	for i, column := range columns {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		if i >= 2 {
+			updates = append(updates, column+"=EXCLUDED."+column)
+		}
+	}
+
+	sql := "INSERT INTO " + t.table + "(" + strings.Join(columns, ",") + ",updated) VALUES(" + strings.Join(placeholders, ",") + ",now()) " +
+		"ON CONFLICT (" + t.foreignKey + ",locale) DO UPDATE SET " + strings.Join(updates, ",") + " RETURNING " + t.columns()
+	response, err := t.scan(tx.QueryRow(sql, args...))
+	if err == nil {
+		err = bumpVersion(tx, t.parentTable, params["id"])
+	}
+	if err == nil {
+		action := AuditUpdate
+		if before == nil {
+			action = AuditCreate
+		}
+		err = app.recordAudit(tx, req, auditEntry{action: action, resourceType: t.auditType, resourceID: params["id"] + "/" + params["locale"],
+			before: before, after: response})
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to save translation")
+		return
+	}
+	writer.Header().Set("ETag", versionETag(version+1, ""))
+	app.RenderJson(writer, http.StatusOK, response)
+}
+
+// lockTranslationParent locks the item a translation belongs to and checks
+// ifMatch against its version, which a translation change bumps. It returns
+// the version before the change.
+func lockTranslationParent(tx dbExecutor, t translatable, id string, ifMatch string) (int, *ErrorResponse) {
+	var version int
+	err := tx.QueryRow("SELECT version FROM "+t.parentTable+" WHERE uid=$1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&version)
+	if err == dbsql.ErrNoRows {
+		return 0, &ErrorResponse{Status: http.StatusNotFound, Error: NotFoundError, Message: fmt.Sprintf("Item [%s] not found", id)}
+	}
+	if err != nil {
+		return 0, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to lock item"}
+	}
+	return version, matchVersion(ifMatch, version, true)
+}
+
+// listTranslations lists the translations of an item. Items the caller may
+// not see, such as drafts for the public or trashed items, are not found.
+func (app *App) listTranslations(writer http.ResponseWriter, req *http.Request, t translatable) {
+	id := mux.Vars(req)["id"]
+	visible := t.visibleSQL
+	if app.authenticate(req).IsEditor() {
+		visible = t.liveSQL
+	}
+	var exists bool
+	sql := "SELECT EXISTS(SELECT 1 FROM " + t.parentTable + " " + t.parentAlias + " WHERE " + t.parentAlias + ".uid=$1 AND " + visible + ")"
+	if err := app.db.QueryRow(sql, id).Scan(&exists); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get translations")
+		return
+	}
+	if !exists {
+		app.RenderErrorResponse(writer, http.StatusNotFound, NotFoundError, fmt.Sprintf("Item [%s] not found", id))
+		return
+	}
+
+	sql = "SELECT " + t.columns() + " FROM " + t.table + " WHERE " + t.foreignKey + "=$1 ORDER BY locale"
+	rows, err := app.db.Query(sql, id)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get translations")
+		return
+	}
+	defer rows.Close()
+
+	translations := []TranslationResponse{}
+	for rows.Next() {
+		response, err := t.scan(rows)
+		if err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get translations")
+			return
+		}
+		translations = append(translations, response)
+	}
+	app.RenderJson(writer, http.StatusOK, translations)
+}
+
+// deleteTranslation deletes a translation of an item whose version matches If-Match
+func (app *App) deleteTranslation(writer http.ResponseWriter, req *http.Request, t translatable) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	params := mux.Vars(req)
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete translation")
+		return
+	}
+	defer tx.Rollback()
+
+	version, ex := lockTranslationParent(tx, t, params["id"], req.Header.Get("If-Match"))
+	if ex != nil {
+		app.renderVersionedError(writer, *ex)
+		return
+	}
+
+	sql := "DELETE FROM " + t.table + " WHERE " + t.foreignKey + "=$1 AND locale=$2 RETURNING " + t.columns()
+	before, err := t.scan(tx.QueryRow(sql, params["id"], params["locale"]))
+	if err == dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusNotFound, NotFoundError, fmt.Sprintf("Translation [%s] not found", params["locale"]))
+		return
+	}
+	if err == nil {
+		err = bumpVersion(tx, t.parentTable, params["id"])
+	}
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditDelete, resourceType: t.auditType, resourceID: params["id"] + "/" + params["locale"],
+			before: before})
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete translation")
+		return
+	}
+	writer.Header().Set("ETag", versionETag(version+1, ""))
+	app.RenderJson(writer, http.StatusOK, nil)
+}
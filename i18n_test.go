@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{"en": "en", "en-US": "en", "EN_us": "en", " tr ": "tr", "": "", "*": "*"}
+	for locale, want := range tests {
+		if got := normalizeLocale(locale); got != want {
+			t.Errorf("normalizeLocale(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestRequestLocale(t *testing.T) {
+	app := &App{conf: &Config{Content: ContentConfig{DefaultLanguage: "tr", Languages: []string{"en", "de"}}}}
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		want   string
+	}{
+		{"nothing asked", "", "", "tr"},
+		{"lang parameter", "?lang=en", "de", "en"},
+		{"lang parameter with region", "?lang=EN-gb", "", "en"},
+		{"unsupported lang parameter", "?lang=fr", "de", "de"},
+		{"single language", "", "en-US", "en"},
+		{"first supported language", "", "fr, de, en", "de"},
+		{"highest quality", "", "en;q=0.5, de;q=0.9, tr;q=0.1", "de"},
+		{"default quality is 1", "", "en;q=0.8, de", "de"},
+		{"equal quality keeps order", "", "en;q=0.5, de;q=0.5", "en"},
+		{"quality 0 refuses", "", "en;q=0, de;q=0", "tr"},
+		{"wildcard ignored", "", "*, en;q=0.1", "en"},
+		{"unsupported only", "", "fr, es", "tr"},
+		{"malformed quality", "", "en;q=abc, de;q=0.5", "en"},
+		{"empty parts", "", ",, ;q=1, de", "de"},
+		{"default language asked", "", "tr-TR, en;q=0.9", "tr"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/news"+test.query, nil)
+			if test.accept != "" {
+				req.Header.Set("Accept-Language", test.accept)
+			}
+			if got := app.requestLocale(req); got != test.want {
+				t.Errorf("requestLocale = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDefaultLanguage(t *testing.T) {
+	app := &App{conf: &Config{}}
+	if got := app.defaultLanguage(); got != "tr" {
+		t.Errorf("defaultLanguage = %q, want tr", got)
+	}
+	if app.isSupportedLanguage("en") {
+		t.Error("en is supported without being configured")
+	}
+}
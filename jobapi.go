@@ -1,10 +1,12 @@
 package main
 
 import (
-	"encoding/json"
+	dbsql "database/sql"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -19,6 +21,12 @@ type JobRequest struct {
 	Department  string `json:"department"`
 	PhoneNumber string `json:"phone_number"`
 	CvMessage   string `json:"cv_message"`
+
+	// Website is a honeypot field hidden from humans, it must stay empty
+	Website string `json:"website,omitempty"`
+	// FormToken is the token issued by GET /job/form-token
+	FormToken    string `json:"form_token,omitempty"`
+	CaptchaToken string `json:"captcha_token,omitempty"`
 }
 
 // JobResponse struct
@@ -31,10 +39,17 @@ type JobResponse struct {
 	Department  string `json:"department"`
 	PhoneNumber string `json:"phone_number"`
 	CvMessage   string `json:"cv_message"`
+	Version     int    `json:"version"`
 }
 
 // AddJobApplications adds a new job to database and creates a json response of the data
 func (app *App) AddJobApplications(writer http.ResponseWriter, req *http.Request) {
+	ip := app.spamGuard.ClientIP(req)
+	if rejection := app.spamGuard.CheckIP(ip); rejection != nil {
+		app.rejectJobApplication(writer, ip, "", nil, rejection)
+		return
+	}
+
 	reqBody, err := ioutil.ReadAll(req.Body)
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
@@ -42,9 +57,15 @@ func (app *App) AddJobApplications(writer http.ResponseWriter, req *http.Request
 	}
 
 	var request JobRequest
-	err = json.Unmarshal(reqBody, &request)
+	err = unmarshalRequest(req, reqBody, &request)
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to convert the input to json")
+		return
+	}
+
+	if rejection := app.spamGuard.CheckApplication(req.Context(), &request, ip); rejection != nil {
+		app.rejectJobApplication(writer, ip, request.Email, reqBody, rejection)
+		return
 	}
 
 	status, message, errValidate := request.ValidateJob()
@@ -52,10 +73,21 @@ func (app *App) AddJobApplications(writer http.ResponseWriter, req *http.Request
 		app.RenderErrorResponse(writer, status, errValidate, message)
 		return
 	}
+	if rejection := app.spamGuard.CheckEmail(request.Email); rejection != nil {
+		app.rejectJobApplication(writer, ip, request.Email, reqBody, rejection)
+		return
+	}
+
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to write job")
+		return
+	}
+	defer tx.Rollback()
 
 	sql := fmt.Sprint("INSERT INTO job_application(first_name,last_name,email,department,phone_number,cv_message,created) VALUES($1,$2,$3,$4,$5,$6,$7) returning uid;")
 	var lastInsertId int
-	err = app.db.QueryRow(
+	err = tx.QueryRow(
 		sql,
 		request.FirstName,
 		request.LastName,
@@ -70,36 +102,91 @@ func (app *App) AddJobApplications(writer http.ResponseWriter, req *http.Request
 		return
 	}
 
-	app.RenderJson(
-		writer,
-		http.StatusOK,
-		JobResponse{
-			FirstName:   request.FirstName,
-			LastName:    request.LastName,
-			Email:       request.Email,
-			Department:  request.Department,
-			PhoneNumber: request.PhoneNumber,
-			CvMessage:   request.CvMessage,
-			ID:          lastInsertId,
-		},
-	)
+	// Emails are only queued here, the outbox worker delivers them later
+	err = app.notifier.JobApplicationReceived(tx, lastInsertId, request)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to queue job notifications")
+		return
+	}
+
+	response := JobResponse{
+		FirstName:   request.FirstName,
+		LastName:    request.LastName,
+		Email:       request.Email,
+		Department:  request.Department,
+		PhoneNumber: request.PhoneNumber,
+		CvMessage:   request.CvMessage,
+		ID:          lastInsertId,
+		Version:     1,
+	}
+	err = app.recordAudit(tx, req, auditEntry{action: AuditCreate, resourceType: AuditJob, resourceID: lastInsertId, after: response})
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to write job")
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to write job")
+		return
+	}
+
+	app.RenderJson(writer, http.StatusOK, response)
 
 }
 
-// GetJobApplications gets all job applications from database with id and creates a json response of the data
+// jobDateLayout is the layout of the since and until job filters
+const jobDateLayout = "2006-01-02"
+
+// addJobFilters adds the department, email, q, since and until filters of the
+// job application list to where. since and until are inclusive dates.
+func addJobFilters(where *whereBuilder, query url.Values) error {
+	if department := query.Get("department"); department != "" {
+		where.Add("lower(department)=lower(?)", department)
+	}
+	if email := query.Get("email"); email != "" {
+		where.Add("lower(email)=lower(?)", email)
+	}
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		where.Add("(first_name || ' ' || last_name) ILIKE ?", "%"+escapeLike(q)+"%")
+	}
+	for param, condition := range map[string]string{"since": "created >= ?", "until": "created <= ?"} {
+		if value := query.Get(param); value != "" {
+			date, err := time.Parse(jobDateLayout, value)
+			if err != nil {
+				return fmt.Errorf("%s must be a date formatted as YYYY-MM-DD", param)
+			}
+			where.Add(condition, date)
+		}
+	}
+	return nil
+}
+
+// GetJobApplications gets all job applications from database with id and creates a json response of the data.
+// The list can be filtered by department, email, q (applicant name) and since/until (YYYY-MM-DD).
 func (app *App) GetJobApplications(writer http.ResponseWriter, req *http.Request) {
-	sql := fmt.Sprintf("SELECT uid,first_name,last_name,email,department,phone_number,cv_message FROM job_application")
-	rows, err := app.db.Query(sql)
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	var where whereBuilder
+	where.Add("deleted_at IS NULL")
+	if err := addJobFilters(&where, req.URL.Query()); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	sql := "SELECT uid,first_name,last_name,email,department,phone_number,cv_message,version FROM job_application" +
+		where.SQL() + " ORDER BY uid"
+	rows, err := app.db.Query(sql, where.Args()...)
 	if err != nil {
 		ex := ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed get job application"}
 		app.RenderError(writer, ex)
 		return
 	}
+	defer rows.Close()
 
 	var jobResponses []JobResponse
 	for rows.Next() {
 		resp := JobResponse{}
-		rows.Scan(&resp.ID, &resp.FirstName, &resp.LastName, &resp.Email, &resp.Department, &resp.PhoneNumber, &resp.CvMessage)
+		rows.Scan(&resp.ID, &resp.FirstName, &resp.LastName, &resp.Email, &resp.Department, &resp.PhoneNumber, &resp.CvMessage, &resp.Version)
 		jobResponses = append(jobResponses, resp)
 	}
 	app.RenderJson(writer, http.StatusOK, jobResponses)
@@ -107,39 +194,32 @@ func (app *App) GetJobApplications(writer http.ResponseWriter, req *http.Request
 
 // FindJobApplicationByID finds job applications from database with id and creates a json response of the data
 func (app *App) FindJobApplicationByID(writer http.ResponseWriter, req *http.Request) {
-	params := mux.Vars(req)
-	sql := fmt.Sprint("SELECT uid,first_name,last_name,email,department,phone_number,cv_message FROM job_application WHERE uid=$1")
-
-	rows, err := app.db.Query(sql, params["id"])
-	if err != nil {
-		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed find job application")
+	if !app.requireEditor(writer, req) {
 		return
 	}
+	params := mux.Vars(req)
+	sql := fmt.Sprint("SELECT uid,first_name,last_name,email,department,phone_number,cv_message,version FROM job_application WHERE uid=$1 AND deleted_at IS NULL")
 
 	resp := JobResponse{}
-	rows.Next()
-	err = rows.Scan(&resp.ID, &resp.FirstName, &resp.LastName, &resp.Email, &resp.Department, &resp.PhoneNumber, &resp.CvMessage)
-	if err != nil || resp.ID == 0 {
+	err := app.db.QueryRow(sql, params["id"]).Scan(&resp.ID, &resp.FirstName, &resp.LastName, &resp.Email, &resp.Department,
+		&resp.PhoneNumber, &resp.CvMessage, &resp.Version)
+	if err == dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Job application [%s] not found", params["id"]))
+		return
+	}
+	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to fetch job application")
 		return
 	}
 
-	app.RenderJson(writer, http.StatusOK, resp)
+	app.renderVersioned(writer, req, versionETag(resp.Version, ""), resp)
 }
 
-// DeleteJob deletes job application from database with id and creates a json response of the data
+// DeleteJob moves a job application to the trash and creates a json response of the data
 func (app *App) DeleteJob(writer http.ResponseWriter, req *http.Request) {
-	params := mux.Vars(req)
-	sql := fmt.Sprint("DELETE FROM job_application WHERE uid=$1")
-
-	rows, err := app.db.Query(sql, params["id"])
-	if err != nil {
-		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete job application")
-		return
+	if app.softDelete(writer, req, trashJobs) {
+		app.RenderJson(writer, http.StatusOK, nil)
 	}
-	rows.Next()
-	app.RenderJson(writer, http.StatusOK, nil)
-
 }
 
 // ValidateJob validates request
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jobExportColumn is a column that can be selected in a job application export
+type jobExportColumn struct {
+	name    string
+	sql     string
+	numeric bool
+}
+
+// jobExportColumns are the exportable columns in their default order
+var jobExportColumns = []jobExportColumn{
+	{name: "id", sql: "uid::text", numeric: true},
+	{name: "first_name", sql: "first_name"},
+	{name: "last_name", sql: "last_name"},
+	{name: "email", sql: "email"},
+	{name: "department", sql: "department"},
+	{name: "phone_number", sql: "phone_number"},
+	{name: "cv_message", sql: "cv_message"},
+	{name: "created", sql: "COALESCE(to_char(created,'YYYY-MM-DD'),'')"},
+}
+
+// utf8BOM lets spreadsheet applications detect UTF-8 in CSV files, without it
+// Excel shows Turkish characters such as ş and ğ as mojibake
+const utf8BOM = "\ufeff"
+
+// jobExportWriter writes the rows of an export in one file format
+type jobExportWriter interface {
+	WriteRow(columns []jobExportColumn, values []string) error
+	Close() error
+}
+
+// selectJobExportColumns resolves the comma separated columns parameter,
+// all columns are exported when it is empty
+func selectJobExportColumns(value string) ([]jobExportColumn, error) {
+	if strings.TrimSpace(value) == "" {
+		return jobExportColumns, nil
+	}
+	var columns []jobExportColumn
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, column := range jobExportColumns {
+			if column.name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return columns, nil
+}
+
+// ExportJobApplications streams the job applications matching the list filters
+// as a CSV or XLSX file. Query parameters: format (csv or xlsx, default csv),
+// columns (comma separated, default all) and the filters of GET /job.
+func (app *App) ExportJobApplications(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	query := req.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		err := fmt.Errorf("unsupported export format %q", format)
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "format must be csv or xlsx")
+		return
+	}
+	columns, err := selectJobExportColumns(query.Get("columns"))
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	var where whereBuilder
+	where.Add("deleted_at IS NULL")
+	if err = addJobFilters(&where, query); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	expressions := make([]string, len(columns))
+	for i, column := range columns {
+		expressions[i] = column.sql
+	}
+	sql := "SELECT " + strings.Join(expressions, ",") + " FROM job_application" + where.SQL() + " ORDER BY uid"
+	rows, err := app.db.Query(sql, where.Args()...)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed export job applications")
+		return
+	}
+	defer rows.Close()
+
+	filename := "job-applications-" + time.Now().Format("2006-01-02") + "." + format
+	writer.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	var export jobExportWriter
+	if format == "xlsx" {
+		writer.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		export, err = newXLSXExportWriter(writer)
+	} else {
+		writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		export, err = newCSVExportWriter(writer)
+	}
+
+	// Once the headers are sent a failure can only be logged, the export is
+	// left unfinished so that it does not look complete
+	logger := logrus.WithField("request_id", requestID(req))
+	if err == nil {
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = column.name
+		}
+		err = export.WriteRow(nil, header)
+	}
+	values := make([]string, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	for err == nil && rows.Next() {
+		if err = rows.Scan(targets...); err == nil {
+			err = export.WriteRow(columns, values)
+		}
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err == nil {
+		err = export.Close()
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed export job applications")
+	}
+}
+
+// csvExportWriter writes an export as UTF-8 CSV
+type csvExportWriter struct {
+	writer  *csv.Writer
+	flusher http.Flusher
+	rows    int
+}
+
+func newCSVExportWriter(writer http.ResponseWriter) (*csvExportWriter, error) {
+	if _, err := io.WriteString(writer, utf8BOM); err != nil {
+		return nil, err
+	}
+	flusher, _ := writer.(http.Flusher)
+	return &csvExportWriter{writer: csv.NewWriter(writer), flusher: flusher}, nil
+}
+
+// WriteRow writes a row, cells that a spreadsheet would evaluate as a formula are
+// prefixed with a quote
+func (w *csvExportWriter) WriteRow(columns []jobExportColumn, values []string) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		if isFormulaCell(value) {
+			value = "'" + value
+		}
+		record[i] = value
+	}
+	if err := w.writer.Write(record); err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%100 == 0 {
+		w.writer.Flush()
+		if w.flusher != nil {
+			w.flusher.Flush()
+		}
+	}
+	return w.writer.Error()
+}
+
+// isFormulaCell reports whether a spreadsheet would evaluate the value as a
+// formula. A leading tab or carriage return is dropped by some spreadsheets,
+// which then see the formula behind it. Phone numbers such as
+// +90 212 555 00 00 are left alone.
+func isFormulaCell(value string) bool {
+	if value == "" {
+		return false
+	}
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return true
+	case '+', '-':
+		return strings.Trim(value, "+-0123456789 ()") != ""
+	}
+	return false
+}
+
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// xlsxExportParts are the fixed parts of a workbook with a single sheet
+var xlsxExportParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Job applications" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxExportWriter streams an export into the sheet of an XLSX workbook. Text is
+// written as inline strings so no shared string table has to be buffered.
+type xlsxExportWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+}
+
+func newXLSXExportWriter(writer io.Writer) (*xlsxExportWriter, error) {
+	archive := zip.NewWriter(writer)
+	for _, part := range xlsxExportParts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(entry)
+	sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return &xlsxExportWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (w *xlsxExportWriter) WriteRow(columns []jobExportColumn, values []string) error {
+	w.row++
+	row := strconv.Itoa(w.row)
+	w.sheet.WriteString(`<row r="` + row + `">`)
+	for i, value := range values {
+		cell := xlsxColumnName(i) + row
+		if columns != nil && columns[i].numeric {
+			w.sheet.WriteString(`<c r="` + cell + `"><v>` + value + `</v></c>`)
+			continue
+		}
+		w.sheet.WriteString(`<c r="` + cell + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(w.sheet, []byte(value)); err != nil {
+			return err
+		}
+		w.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxExportWriter) Close() error {
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// xlsxColumnName returns the spreadsheet column name (A, B, ..., AA) of a zero based index
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
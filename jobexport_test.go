@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsFormulaCell(t *testing.T) {
+	tests := map[string]bool{
+		"":                   false,
+		"Ayşe":               false,
+		"ayse@codonex.com":   false,
+		"=1+1":               true,
+		"@SUM(A1:A2)":        true,
+		"+90 212 555 00 00":  false,
+		"+90 (212) 555-0000": false,
+		"-5":                 false,
+		"+cmd|' /C calc'!A0": true,
+		"-2+3+cmd|' /C'!A0":  true,
+		"\t=1+1":             true,
+		"\r=1+1":             true,
+		"\tindented":         true,
+		"a\t=1+1":            false,
+		" =1+1":              false,
+	}
+	for value, want := range tests {
+		if got := isFormulaCell(value); got != want {
+			t.Errorf("isFormulaCell(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA", 16383: "XFD"}
+	for index, want := range tests {
+		if got := xlsxColumnName(index); got != want {
+			t.Errorf("xlsxColumnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
+
+func TestSelectJobExportColumns(t *testing.T) {
+	names := func(columns []jobExportColumn) []string {
+		var result []string
+		for _, column := range columns {
+			result = append(result, column.name)
+		}
+		return result
+	}
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"", names(jobExportColumns), false},
+		{"  ", names(jobExportColumns), false},
+		{"email", []string{"email"}, false},
+		{"last_name, first_name ,id", []string{"last_name", "first_name", "id"}, false},
+		{"email,email", []string{"email", "email"}, false},
+		{"email,password", nil, true},
+		{"email,", nil, true},
+		{"EMAIL", nil, true},
+	}
+	for _, test := range tests {
+		columns, err := selectJobExportColumns(test.value)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("selectJobExportColumns(%q) = %v, want an error", test.value, names(columns))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("selectJobExportColumns(%q): %v", test.value, err)
+			continue
+		}
+		if got := names(columns); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("selectJobExportColumns(%q) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
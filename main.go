@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"os"
 
 	"github.com/sirupsen/logrus"
 )
@@ -9,6 +10,7 @@ import (
 //Server code borrowed from: https://dev.to/koddr/let-s-write-config-for-your-golang-web-app-on-right-way-yaml-5ggp
 
 var configPath = flag.String("config", "./config.yaml", "path to config file")
+var printConfig = flag.Bool("print-config", false, "print the effective config with secrets redacted and exit")
 
 const AppName = "cerci-platform"
 
@@ -16,14 +18,17 @@ func main() {
 	flag.Parse()
 	logrus.SetFormatter(&logrus.JSONFormatter{})
 
-	var conf *Config
-	var err error
-	if *configPath != "" {
-		conf, err = NewConfig(*configPath)
-		if err != nil {
-			logrus.WithError(err).Fatal("Failed to read configuration file")
+	conf, err := NewConfig(*configPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to read configuration file")
+	}
+	if *printConfig {
+		if err = conf.WriteRedacted(os.Stdout); err != nil {
+			logrus.WithError(err).Fatal("Failed to print config")
 		}
+		return
 	}
+	conf.Log.apply()
 	logrus.WithField("configFile", *configPath).Info("Read config file")
 	conf.AppName = AppName
 	app := NewApp(conf)
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+)
+
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request-id"
+	principalKey contextKey = "principal"
+)
+
+// requestIDPattern limits the request ids accepted from clients
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// requestIDMiddleware tags every request with an id, taken from X-Request-ID
+// when the client sent a usable one. The id is echoed in the response.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get("X-Request-ID")
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		writer.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(writer, req.WithContext(context.WithValue(req.Context(), requestIDKey, id)))
+	})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// requestID returns the id of the request, empty outside of the middleware
+func requestID(req *http.Request) string {
+	id, _ := req.Context().Value(requestIDKey).(string)
+	return id
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Media types the api reads and writes
+const (
+	MediaJSON = "application/json"
+	MediaXML  = "application/xml"
+	MediaCSV  = "text/csv"
+)
+
+// negotiatedWriter carries the response format chosen for a request to RenderJson
+type negotiatedWriter struct {
+	http.ResponseWriter
+	format string
+}
+
+// negotiateFormat picks the response format from an Accept header. It
+// returns false when none of the accepted types can be produced.
+func negotiateFormat(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return MediaJSON, true
+	}
+
+	type weighted struct {
+		format  string
+		quality float64
+	}
+	var candidates []weighted
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = value
+			}
+		}
+		format := ""
+		switch mediaType {
+		case "*/*", "application/*", MediaJSON:
+			format = MediaJSON
+		case MediaXML, "text/xml":
+			format = MediaXML
+		case MediaCSV, "text/*":
+			format = MediaCSV
+		}
+		if format != "" && quality > 0 {
+			candidates = append(candidates, weighted{format, quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	return candidates[0].format, true
+}
+
+// requestFormat returns the format of the request body from its Content-Type
+func requestFormat(req *http.Request) (string, error) {
+	contentType := req.Header.Get("Content-Type")
+	if contentType == "" {
+		return MediaJSON, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", err
+	}
+	switch mediaType {
+	case MediaJSON:
+		return MediaJSON, nil
+	case MediaXML, "text/xml":
+		return MediaXML, nil
+	}
+	return "", fmt.Errorf("unsupported content type %q", mediaType)
+}
+
+// hasBody reports whether the request carries a body
+func hasBody(req *http.Request) bool {
+	return req.ContentLength > 0 || (req.ContentLength < 0 && req.Body != nil && req.Body != http.NoBody)
+}
+
+// negotiate answers 406 Not Acceptable and 415 Unsupported Media Type before
+// the handler runs, and passes the chosen response format on to RenderJson
+func (app *App) negotiate(handler http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Add("Vary", "Accept")
+		format, ok := negotiateFormat(req.Header.Get("Accept"))
+		if !ok {
+			app.RenderErrorResponse(writer, http.StatusNotAcceptable, fmt.Errorf("cannot produce %q", req.Header.Get("Accept")),
+				"Supported response types are application/json, application/xml and text/csv")
+			return
+		}
+		if hasBody(req) {
+			if _, err := requestFormat(req); err != nil {
+				app.RenderErrorResponse(writer, http.StatusUnsupportedMediaType, err,
+					"Supported request types are application/json and application/xml")
+				return
+			}
+		}
+		handler(&negotiatedWriter{ResponseWriter: writer, format: format}, req)
+	}
+}
+
+// unmarshalRequest decodes a request body sent as JSON or XML into v
+func unmarshalRequest(req *http.Request, body []byte, v interface{}) error {
+	format, err := requestFormat(req)
+	if err != nil {
+		return err
+	}
+	if format == MediaXML {
+		if body, err = xmlToJSON(body, reflect.TypeOf(v)); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(body, v)
+}
+
+// renderNegotiated writes data in format, data is first marshaled to JSON so
+// every format uses the json field names
+func (app *App) renderNegotiated(writer http.ResponseWriter, status int, format string, data interface{}) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case MediaXML:
+		err = writeXML(&buf, xmlRootName(data), jsonData)
+	case MediaCSV:
+		err = writeCSV(&buf, reflect.TypeOf(data), jsonData)
+	}
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", format+"; charset=utf-8")
+	writer.WriteHeader(status)
+	writer.Write(buf.Bytes())
+}
+
+// xmlRootName names the document element of a response
+func xmlRootName(data interface{}) string {
+	t := reflect.TypeOf(data)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == nil:
+		return "response"
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return "items"
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return t.Name()
+	}
+	return "response"
+}
+
+// xmlName turns a json key into a valid xml element name
+func xmlName(key string) string {
+	var builder strings.Builder
+	for i, char := range key {
+		valid := char == '_' || unicode.IsLetter(char) || (i > 0 && (char == '-' || char == '.' || unicode.IsDigit(char)))
+		if !valid {
+			if i == 0 && unicode.IsDigit(char) {
+				builder.WriteRune('_')
+				builder.WriteRune(char)
+				continue
+			}
+			char = '_'
+		}
+		builder.WriteRune(char)
+	}
+	if builder.Len() == 0 {
+		return "_"
+	}
+	return builder.String()
+}
+
+// writeXML converts a json document to xml. Objects become elements named
+// after their keys and array entries become <item> elements.
+func writeXML(w io.Writer, root string, jsonData []byte) error {
+	io.WriteString(w, xml.Header)
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	encoder := xml.NewEncoder(w)
+	if err := writeXMLValue(decoder, encoder, root); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+func writeXMLValue(decoder *json.Decoder, encoder *xml.Encoder, name string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err = encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		isObject := value == '{'
+		for decoder.More() {
+			childName := "item"
+			if isObject {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				childName = xmlName(key.(string))
+			}
+			if err = writeXMLValue(decoder, encoder, childName); err != nil {
+				return err
+			}
+		}
+		// closing delimiter
+		if _, err = decoder.Token(); err != nil {
+			return err
+		}
+	case nil:
+	case string:
+		err = encoder.EncodeToken(xml.CharData(value))
+	default:
+		err = encoder.EncodeToken(xml.CharData(fmt.Sprint(value)))
+	}
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlNode is an element of a parsed xml request
+type xmlNode struct {
+	name     string
+	text     string
+	children []*xmlNode
+}
+
+// parseXML reads the document element of an xml document
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("empty xml document")
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: token.Name.Local}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(token)
+			}
+		case xml.EndElement:
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return node, nil
+			}
+		}
+	}
+}
+
+// xmlToJSON converts an xml request into json for the type it is decoded
+// into, the mirror image of writeXML. The type decides whether text is read
+// as a string, number or boolean.
+func xmlToJSON(data []byte, t reflect.Type) ([]byte, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+	value, err := xmlValue(root, t)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func xmlValue(node *xmlNode, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	text := strings.TrimSpace(node.text)
+
+	switch {
+	case t == timeType:
+		return text, nil
+	case t.Kind() == reflect.Struct:
+		fields := jsonFields(t)
+		object := map[string]interface{}{}
+		for _, child := range node.children {
+			field, ok := fields[child.name]
+			if !ok {
+				continue
+			}
+			value, err := xmlValue(child, field)
+			if err != nil {
+				return nil, err
+			}
+			object[child.name] = value
+		}
+		return object, nil
+	case t.Kind() == reflect.Map:
+		object := map[string]interface{}{}
+		for _, child := range node.children {
+			value, err := xmlValue(child, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			object[child.name] = value
+		}
+		return object, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// base64, like json
+		return text, nil
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		items := []interface{}{}
+		for _, child := range node.children {
+			value, err := xmlValue(child, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case t.Kind() == reflect.Bool:
+		if text == "" {
+			return nil, nil
+		}
+		return strconv.ParseBool(text)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		if text == "" {
+			return nil, nil
+		}
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return nil, fmt.Errorf("%s must be a number", node.name)
+		}
+		return json.Number(text), nil
+	case t.Kind() == reflect.Interface && len(node.children) > 0:
+		return xmlValue(node, reflect.TypeOf(map[string]interface{}{}))
+	}
+	return text, nil
+}
+
+// jsonFields maps the json names of the exported fields of a struct to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := jsonFieldName(field); name != "" {
+			fields[name] = field.Type
+		}
+	}
+	return fields
+}
+
+// jsonFieldName returns the json name of a struct field, empty when it is not encoded
+func jsonFieldName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// csvColumns returns the columns of a list of t, in field order
+func csvColumns(t reflect.Type) []string {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	var columns []string
+	if t != nil && t.Kind() == reflect.Struct && t != timeType {
+		for i := 0; i < t.NumField(); i++ {
+			if name := jsonFieldName(t.Field(i)); name != "" {
+				columns = append(columns, name)
+			}
+		}
+	}
+	return columns
+}
+
+// writeCSV writes a json array of objects as csv with a header row. A single
+// object becomes a single row. Nested values are written as json.
+func writeCSV(w io.Writer, t reflect.Type, jsonData []byte) error {
+	var rows []map[string]json.RawMessage
+	trimmed := bytes.TrimSpace(jsonData)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		if err := json.Unmarshal(trimmed, &rows); err != nil {
+			return fmt.Errorf("csv needs a list of objects: %v", err)
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &row); err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	case !bytes.Equal(trimmed, []byte("null")):
+		return errors.New("csv needs a list of objects")
+	}
+
+	columns := csvColumns(t)
+	if len(columns) == 0 {
+		seen := map[string]bool{}
+		for _, row := range rows {
+			var keys []string
+			for key := range row {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			columns = append(columns, keys...)
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			record[i] = csvCell(row[column])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvCell formats a json value as a csv cell
+func csvCell(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	return string(raw)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", MediaJSON, true},
+		{"application/json", MediaJSON, true},
+		{"*/*", MediaJSON, true},
+		{"application/*", MediaJSON, true},
+		{"application/xml", MediaXML, true},
+		{"text/xml", MediaXML, true},
+		{"text/csv", MediaCSV, true},
+		{"text/*", MediaCSV, true},
+		{"application/xml, application/json", MediaXML, true},
+		{"application/xml;q=0.5, text/csv", MediaCSV, true},
+		{"text/csv;q=0.2, application/xml;q=0.9, */*;q=0.1", MediaXML, true},
+		{"application/json;q=0, application/xml", MediaXML, true},
+		{"application/xml;q=abc", MediaXML, true},
+		{"text/html, image/png, application/json;q=0.1", MediaJSON, true},
+		{"image/png", "", false},
+		{"text/html, application/json;q=0", "", false},
+		{";;;", "", false},
+	}
+	for _, test := range tests {
+		got, ok := negotiateFormat(test.accept)
+		if got != test.want || ok != test.ok {
+			t.Errorf("negotiateFormat(%q) = %q, %v, want %q, %v", test.accept, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+// xmlTestItem covers the kinds xmlValue converts
+type xmlTestItem struct {
+	ID        int               `json:"id"`
+	Title     string            `json:"title"`
+	Score     float64           `json:"score"`
+	Published bool              `json:"published"`
+	Parent    *int              `json:"parent_id"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	Created   time.Time         `json:"created"`
+	Author    xmlTestAuthor     `json:"author"`
+	Ignored   string            `json:"-"`
+}
+
+type xmlTestAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	parent := 3
+	tests := []struct {
+		name string
+		item xmlTestItem
+	}{
+		{"empty", xmlTestItem{Tags: []string{}, Labels: map[string]string{}}},
+		{"full", xmlTestItem{
+			ID: 7, Title: "Çerçi <news> & \"quotes\"", Score: 2.5, Published: true, Parent: &parent,
+			Tags: []string{"go", "xml"}, Labels: map[string]string{"lang": "tr", "kind": "post"},
+			Created: time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC), Author: xmlTestAuthor{Name: "Ada", Email: "ada@example.com"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jsonData, err := json.Marshal(test.item)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err = writeXML(&buf, xmlRootName(test.item), jsonData); err != nil {
+				t.Fatal(err)
+			}
+			converted, err := xmlToJSON(buf.Bytes(), reflect.TypeOf(&xmlTestItem{}))
+			if err != nil {
+				t.Fatalf("xmlToJSON(%s): %v", buf.String(), err)
+			}
+			var got xmlTestItem
+			if err = json.Unmarshal(converted, &got); err != nil {
+				t.Fatalf("%s: %v", converted, err)
+			}
+			if !reflect.DeepEqual(got, test.item) {
+				t.Errorf("round trip through\n%s\n got %+v\nwant %+v", buf.String(), got, test.item)
+			}
+		})
+	}
+}
+
+func TestWriteXML(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+		json string
+		want string
+	}{
+		{"invalid names", "response", `{"1a":1,"b c":null}`, `<response><_1a>1</_1a><b_c></b_c></response>`},
+		{"array", "items", `["x",2,true]`, `<items><item>x</item><item>2</item><item>true</item></items>`},
+		{"escaped text", "response", `{"s":"a<b&c"}`, `<response><s>a&lt;b&amp;c</s></response>`},
+		{"large number", "response", `{"n":12345678901234567890}`, `<response><n>12345678901234567890</n></response>`},
+		{"nested", "response", `{"a":{"b":[{"c":1}]}}`, `<response><a><b><item><c>1</c></item></b></a></response>`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeXML(&buf, test.root, []byte(test.json)); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"); got != test.want {
+				t.Errorf("got  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestXMLToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		xml     string
+		want    string
+		wantErr bool
+	}{
+		{"unknown elements ignored", `<item><id>1</id><secret>x</secret></item>`, `{"id":1}`, false},
+		{"root name ignored", `<anything><title> padded </title></anything>`, `{"title":"padded"}`, false},
+		{"empty number is null", `<item><id></id><parent_id/></item>`, `{"id":null,"parent_id":null}`, false},
+		{"array items of any name", `<item><tags><tag>a</tag><x>b</x></tags></item>`, `{"tags":["a","b"]}`, false},
+		{"not a number", `<item><id>seven</id></item>`, "", true},
+		{"not a bool", `<item><published>maybe</published></item>`, "", true},
+		{"empty document", ``, "", true},
+		{"malformed", `<item><id>1</item>`, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := xmlToJSON([]byte(test.xml), reflect.TypeOf(xmlTestItem{}))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	type row struct {
+		ID   int               `json:"id"`
+		Name string            `json:"name"`
+		Tags []string          `json:"tags"`
+		Skip string            `json:"-"`
+		Meta map[string]string `json:"meta,omitempty"`
+	}
+	tests := []struct {
+		name    string
+		t       reflect.Type
+		json    string
+		want    string
+		wantErr bool
+	}{
+		{"list of structs", reflect.TypeOf([]row{}), `[{"id":1,"name":"a, \"b\"","tags":["x"]},{"id":2,"name":"line\nbreak","tags":null}]`,
+			"id,name,tags,meta\n1,\"a, \"\"b\"\"\",\"[\"\"x\"\"]\",\n2,\"line\nbreak\",,\n", false},
+		{"single struct", reflect.TypeOf(&row{}), `{"id":3,"name":"one","tags":[],"meta":{"k":"v"}}`,
+			"id,name,tags,meta\n3,one,[],\"{\"\"k\"\":\"\"v\"\"}\"\n", false},
+		{"empty list", reflect.TypeOf([]row{}), `[]`, "id,name,tags,meta\n", false},
+		{"null", reflect.TypeOf([]row{}), `null`, "id,name,tags,meta\n", false},
+		{"maps use sorted keys", reflect.TypeOf([]map[string]interface{}{}), `[{"b":2,"a":true},{"c":"x","a":false}]`,
+			"a,b,c\ntrue,2,\nfalse,,x\n", false},
+		{"scalar", reflect.TypeOf(""), `"text"`, "", true},
+		{"list of scalars", reflect.TypeOf([]int{}), `[1,2]`, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeCSV(&buf, test.t, []byte(test.json))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got %q, want an error", buf.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("got  %q\nwant %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	app := &App{}
+	handler := app.negotiate(func(writer http.ResponseWriter, req *http.Request) {
+		var request RelationRequest
+		if hasBody(req) {
+			body := new(bytes.Buffer)
+			body.ReadFrom(req.Body)
+			if err := unmarshalRequest(req, body.Bytes(), &request); err != nil {
+				app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to parse request body")
+				return
+			}
+		}
+		app.RenderJson(writer, http.StatusOK, RelationResponse{Name: request.Name, ParentID: request.ParentID})
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		accept      string
+		contentType string
+		body        string
+		status      int
+		mediaType   string
+		contains    string
+	}{
+		{"json by default", "GET", "", "", "", http.StatusOK, "application/json", `"name":""`},
+		{"xml", "GET", "application/xml", "", "", http.StatusOK, "application/xml; charset=utf-8", "<RelationResponse>"},
+		{"csv", "GET", "text/csv", "", "", http.StatusOK, "text/csv; charset=utf-8", "id,name,type_id,parent_id,path_id\n"},
+		{"not acceptable", "GET", "image/png", "", "", http.StatusNotAcceptable, "application/json", "Supported response types"},
+		{"not acceptable html", "GET", "text/html", "", "", http.StatusNotAcceptable, "application/json", `"status":406`},
+		{"json body", "POST", "", "application/json", `{"name":"a","parent_id":2}`, http.StatusOK, "application/json", `"name":"a"`},
+		{"xml body", "POST", "application/json", "application/xml; charset=utf-8",
+			`<RelationRequest><name>b</name><parent_id>4</parent_id></RelationRequest>`, http.StatusOK, "application/json", `"parent_id":4`},
+		{"unsupported body", "POST", "", "text/plain", "name=a", http.StatusUnsupportedMediaType, "application/json", "Supported request types"},
+		{"malformed content type", "POST", "", "application/", "{}", http.StatusUnsupportedMediaType, "application/json", "Supported request types"},
+		{"content type without body", "GET", "", "text/plain", "", http.StatusOK, "application/json", `"name":""`},
+		{"bad xml body", "POST", "", "text/xml", "<RelationRequest><parent_id>x</parent_id></RelationRequest>", http.StatusBadRequest, "application/json", "Failed to parse request body"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/relation", strings.NewReader(test.body))
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			handler(recorder, req)
+
+			if recorder.Code != test.status {
+				t.Errorf("status %d, want %d: %s", recorder.Code, test.status, recorder.Body.String())
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != test.mediaType {
+				t.Errorf("Content-Type %q, want %q", contentType, test.mediaType)
+			}
+			if vary := recorder.Header().Get("Vary"); vary != "Accept" {
+				t.Errorf("Vary %q, want Accept", vary)
+			}
+			if !strings.Contains(recorder.Body.String(), test.contains) {
+				t.Errorf("body %s does not contain %s", recorder.Body.String(), test.contains)
+			}
+		})
+	}
+}
@@ -1,34 +1,131 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	dbsql "database/sql"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
 )
 
+// News item statuses
+const (
+	NewsDraft     = "draft"
+	NewsPublished = "published"
+	NewsArchived  = "archived"
+)
+
+// newsLiveSQL matches news items (n) that are not in the trash
+const newsLiveSQL = "n.deleted_at IS NULL"
+
+// newsVisibleSQL restricts news_item rows to the ones the public may see
+const newsVisibleSQL = newsLiveSQL + " AND n.status='published' AND (n.publish_at IS NULL OR n.publish_at <= now()) AND (n.expire_at IS NULL OR n.expire_at > now())"
+
+const newsColumns = "uid,slug,news_title,detail,detail_format,detail_html,news_image,status,publish_at,expire_at,created,version"
+
+// newsLocalizedColumns selects the same columns as newsColumns from newsFrom,
+// preferring the translated title and detail
+const newsLocalizedColumns = "n.uid,n.slug,COALESCE(t.news_title,n.news_title),COALESCE(t.detail,n.detail)," +
+	"COALESCE(t.detail_format,n.detail_format),COALESCE(t.detail_html,n.detail_html),n.news_image,n.status,n.publish_at,n.expire_at,n.created,n.version"
+
 // NewsRequest request struct
 //swagger:model NewsRequest
 type NewsRequest struct {
-	Title     string `json:"title"`
-	Detail    string `json:"detail"`
-	NewsImage []byte `json:"news_image"`
+	Title        string     `json:"title"`
+	Detail       string     `json:"detail"`
+	DetailFormat string     `json:"detail_format,omitempty"` // text (default), markdown or html
+	NewsImage    []byte     `json:"news_image"`
+	Status       string     `json:"status,omitempty"`
+	PublishAt    *time.Time `json:"publish_at,omitempty"`
+	ExpireAt     *time.Time `json:"expire_at,omitempty"`
+	// Tags are created when missing, categories must exist
+	Tags       []string `json:"tags,omitempty"`
+	Categories []string `json:"categories,omitempty"`
 }
 
 // NewsResponse response struct
 //swagger:response NewsResponse
 type NewsResponse struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title"`
-	Detail    string `json:"detail"`
-	NewsImage []byte `json:"news_image"`
+	ID           int        `json:"id"`
+	Slug         string     `json:"slug"`
+	Title        string     `json:"title"`
+	Detail       string     `json:"detail"`
+	DetailFormat string     `json:"detail_format"`
+	DetailHTML   string     `json:"detail_html"` // sanitized html rendering of Detail
+	Excerpt      string     `json:"excerpt"`
+	NewsImage    []byte     `json:"news_image"`
+	Status       string     `json:"status"`
+	PublishAt    *time.Time `json:"publish_at"`
+	ExpireAt     *time.Time `json:"expire_at"`
+	Created      time.Time  `json:"created"`
+	Locale       string     `json:"locale"`
+	Version      int        `json:"version"`
+
+	Tags       []TermResponse `json:"tags"`
+	Categories []TermResponse `json:"categories"`
+	// only filled when a single news item is requested
+	RelatedProjects []RelatedProjectResponse `json:"related_projects,omitempty"`
+}
+
+// PublishRequest request struct
+//swagger:model PublishRequest
+type PublishRequest struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	ExpireAt  *time.Time `json:"expire_at,omitempty"`
+}
+
+const newsExcerptLength = 300
+
+// scanNews reads a news row selected with newsColumns, extra receives any
+// additional selected columns
+func scanNews(row rowScanner, extra ...interface{}) (NewsResponse, error) {
+	response := NewsResponse{}
+	dest := []interface{}{&response.ID, &response.Slug, &response.Title, &response.Detail, &response.DetailFormat,
+		&response.DetailHTML, &response.NewsImage, &response.Status, &response.PublishAt, &response.ExpireAt, &response.Created, &response.Version}
+	err := row.Scan(append(dest, extra...)...)
+	response.Excerpt = excerpt(PlainText(response.DetailHTML), newsExcerptLength)
+	return response, err
+}
+
+// newsFrom returns the FROM clause joining news_item (n) with its translation (t) for locale
+func newsFrom(where *whereBuilder, locale string) string {
+	return "news_item n LEFT JOIN news_translation t ON t.news_uid=n.uid AND t.locale=" + where.Arg(locale)
+}
+
+// queryLocalizedNews runs a localized news query and returns the news items
+func (app *App) queryLocalizedNews(where *whereBuilder, from string, suffix string) ([]NewsResponse, error) {
+	sql := "SELECT " + newsLocalizedColumns + ",t.locale FROM " + from + where.SQL() + suffix
+	rows, err := app.db.Query(sql, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var news []NewsResponse
+	for rows.Next() {
+		var translated dbsql.NullString
+		response, err := scanNews(rows, &translated)
+		if err != nil {
+			return nil, err
+		}
+		response.Locale = app.resolvedLocale(translated)
+		news = append(news, response)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return news, attachNewsTerms(app.db, news)
 }
 
 // AddNewsItem adds news item to database and creates a json response of the data
 func (app *App) AddNewsItem(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
 	reqBody, err := ioutil.ReadAll(req.Body)
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
@@ -36,7 +133,7 @@ func (app *App) AddNewsItem(writer http.ResponseWriter, req *http.Request) {
 	}
 
 	var request NewsRequest
-	err = json.Unmarshal(reqBody, &request)
+	err = unmarshalRequest(req, reqBody, &request)
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to convert json code")
 		return
@@ -48,70 +145,451 @@ func (app *App) AddNewsItem(writer http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	sql := fmt.Sprint("INSERT INTO news_item(news_title,detail,news_image,created) VALUES($1,$2,$3,$4) returning uid;")
-	var lastInsertId int
-	err = app.db.QueryRow(sql, request.Title, request.Detail, request.NewsImage, time.Now()).Scan(&lastInsertId)
+	tx, err := app.db.Begin()
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to write news")
 		return
 	}
+	defer tx.Rollback()
+
+	response, ex := app.createNews(tx, req, request)
+	if ex != nil {
+		app.RenderError(writer, *ex)
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to write news")
+		return
+	}
+
+	writer.Header().Set("ETag", versionETag(response.Version, ""))
+	app.RenderJson(writer, http.StatusOK, response)
+
+}
 
-	app.RenderJson(writer, http.StatusOK, NewsResponse{Title: request.Title, Detail: request.Detail, NewsImage: request.NewsImage, ID: lastInsertId})
+// createNews inserts a validated news item with its taxonomies, first revision
+// and audit entry
+func (app *App) createNews(tx dbExecutor, req *http.Request, request NewsRequest) (NewsResponse, *ErrorResponse) {
+	detailHTML, err := RenderDetail(request.Detail, request.DetailFormat)
+	if err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to render detail"}
+	}
 
+	sql := fmt.Sprint("INSERT INTO news_item(slug,news_title,detail,detail_format,detail_html,news_image,status,publish_at,expire_at,created) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) returning " + newsColumns)
+	var response NewsResponse
+	_, err = writeWithSlug(tx, func() (string, error) {
+		return uniqueSlug(tx, SlugNews, request.Title, 0)
+	}, func(slug string) (err error) {
+		response, err = scanNews(tx.QueryRow(sql, slug, request.Title, request.Detail, request.DetailFormat, detailHTML, request.NewsImage,
+			request.Status, request.PublishAt, request.ExpireAt, time.Now()))
+		return err
+	})
+	if err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to write news"}
+	}
+	if err = setNewsTaxonomies(tx, response.ID, request); err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to write news tags"}
+	}
+	news := []NewsResponse{response}
+	if err = attachNewsTerms(tx, news); err == nil {
+		err = app.saveRequestRevision(tx, req, newsRevisions, response.ID)
+	}
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditCreate, resourceType: AuditNews, resourceID: response.ID, after: news[0]})
+	}
+	if err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to write news"}
+	}
+	return news[0], nil
 }
 
-// GetNewsItems gets all news items from database and creates a json response of the data
+// GetNewsItems gets all news items from database and creates a json response of the data.
+// Anonymous callers only see currently published items, editors may filter by ?status=
 func (app *App) GetNewsItems(writer http.ResponseWriter, req *http.Request) {
-	sql := fmt.Sprintf("SELECT uid,news_title,detail,news_image FROM news_item")
-	rows, err := app.db.Query(sql)
+	locale := app.requestLocale(req)
+	var where whereBuilder
+	from := newsFrom(&where, locale)
+	query := req.URL.Query()
+	for _, tag := range query["tag"] {
+		where.Add(tags.hasTermSQL(), tag)
+	}
+	for _, category := range query["category"] {
+		where.Add(categories.hasTermSQL(), category)
+	}
+	status := query.Get("status")
+	if app.authenticate(req).IsEditor() {
+		where.Add(newsLiveSQL)
+		if status != "" && status != "all" {
+			where.Add("n.status=?", status)
+		}
+	} else {
+		where.Add(newsVisibleSQL)
+	}
+
+	news, err := app.queryLocalizedNews(&where, from, " ORDER BY COALESCE(n.publish_at, n.created) DESC")
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get news items")
 		return
 	}
 
-	var news []NewsResponse
-	for rows.Next() {
-		response := NewsResponse{}
-		rows.Scan(&response.ID, &response.Title, &response.Detail, &response.NewsImage)
-		news = append(news, response)
-	}
-
+	setContentLanguage(writer, locale)
 	app.RenderJson(writer, http.StatusOK, news)
 }
 
 // FindNewsItem finds news item from database with id and creates a json response of the data
 func (app *App) FindNewsItem(writer http.ResponseWriter, req *http.Request) {
 	params := mux.Vars(req)
-	sql := fmt.Sprint("SELECT uid,news_title,detail,news_image FROM news_item WHERE uid=$1")
-	rows, err := app.db.Query(sql, params["id"])
+	locale := app.requestLocale(req)
+	var where whereBuilder
+	from := newsFrom(&where, locale)
+	where.Add("n.uid=?", params["id"])
+	if app.authenticate(req).IsEditor() {
+		where.Add(newsLiveSQL)
+	} else {
+		where.Add(newsVisibleSQL)
+	}
+
+	news, err := app.queryLocalizedNews(&where, from, "")
+	if err != nil || len(news) == 0 {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("News item [%s] not found", params["id"]))
+		return
+	}
+	if news[0].RelatedProjects, err = app.relatedProjects(news[0].ID, locale); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get related projects")
+		return
+	}
+	setContentLanguage(writer, locale)
+	app.renderVersioned(writer, req, versionETag(news[0].Version, locale), news[0])
+}
+
+// FindNewsItemBySlug finds news item by its slug. Old slugs redirect to the current one.
+func (app *App) FindNewsItemBySlug(writer http.ResponseWriter, req *http.Request) {
+	slug := mux.Vars(req)["slug"]
+	locale := app.requestLocale(req)
+	var where whereBuilder
+	from := newsFrom(&where, locale)
+	where.Add("n.slug=?", slug)
+	visibleSQL := newsVisibleSQL
+	if app.authenticate(req).IsEditor() {
+		visibleSQL = newsLiveSQL
+	}
+	where.Add(visibleSQL)
+
+	news, err := app.queryLocalizedNews(&where, from, "")
+	if err == nil && len(news) == 0 && app.redirectSlug(writer, req, SlugNews, slug, visibleSQL) {
+		return
+	}
+	if err != nil || len(news) == 0 {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("News item [%s] not found", slug))
+		return
+	}
+	if news[0].RelatedProjects, err = app.relatedProjects(news[0].ID, locale); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get related projects")
+		return
+	}
+	setContentLanguage(writer, locale)
+	app.renderVersioned(writer, req, versionETag(news[0].Version, locale), news[0])
+}
+
+// UpdateNewsItem updates the content of a news item. A changed title gets a
+// new slug, the old one keeps working as a redirect. publish_at and expire_at
+// are replaced like the other fields, leaving them out clears the schedule.
+// The status is managed with the publish, unpublish and archive endpoints.
+func (app *App) UpdateNewsItem(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	params := mux.Vars(req)
+	reqBody, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed find news")
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
 		return
 	}
 
-	response := NewsResponse{}
-	rows.Next()
-	err = rows.Scan(&response.ID, &response.Title, &response.Detail, &response.NewsImage)
-	if err != nil || response.ID == 0 {
-		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("News item [%s] not found", params["id"]))
+	var request NewsRequest
+	err = unmarshalRequest(req, reqBody, &request)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to convert json code")
+		return
+	}
+
+	status, message, errValidate := request.ValidateNews()
+	if errValidate != nil {
+		app.RenderErrorResponse(writer, status, errValidate, message)
+		return
+	}
+
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news")
+		return
+	}
+	defer tx.Rollback()
+
+	response, ex := app.updateNews(tx, req, params["id"], req.Header.Get("If-Match"), request)
+	if ex != nil {
+		app.renderVersionedError(writer, *ex)
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news")
 		return
 	}
+	writer.Header().Set("ETag", versionETag(response.Version, ""))
 	app.RenderJson(writer, http.StatusOK, response)
 }
 
-// DeleteNewsItem deletes news item from database with id and creates a json response of the data
-func (app *App) DeleteNewsItem(writer http.ResponseWriter, req *http.Request) {
-	params := mux.Vars(req)
-	sql := fmt.Sprint("DELETE FROM news_item WHERE uid=$1")
-	rows, err := app.db.Query(sql, params["id"])
+// updateNews updates the content of a news item whose version matches ifMatch,
+// recording a revision and an audit entry
+func (app *App) updateNews(tx dbExecutor, req *http.Request, id string, ifMatch string, request NewsRequest) (NewsResponse, *ErrorResponse) {
+	current, err := scanNews(tx.QueryRow("SELECT "+newsColumns+" FROM news_item WHERE uid=$1 AND deleted_at IS NULL FOR UPDATE", id))
+	if err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusNotFound, Error: err, Message: fmt.Sprintf("News item [%s] not found", id)}
+	}
+	if ex := matchVersion(ifMatch, current.Version, true); ex != nil {
+		return NewsResponse{}, ex
+	}
+	before := []NewsResponse{current}
+	if err = attachNewsTerms(tx, before); err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to update news"}
+	}
+
+	detailHTML, err := RenderDetail(request.Detail, request.DetailFormat)
+	if err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to render detail"}
+	}
+
+	sql := "UPDATE news_item SET slug=$1, news_title=$2, detail=$3, detail_format=$4, detail_html=$5, news_image=$6, " +
+		"publish_at=$7, expire_at=$8, updated=now(), version=version+1 WHERE uid=$9 RETURNING " + newsColumns
+	var response NewsResponse
+	_, err = writeWithSlug(tx, func() (string, error) {
+		return renameSlug(tx, SlugNews, current.ID, current.Slug, request.Title)
+	}, func(slug string) (err error) {
+		response, err = scanNews(tx.QueryRow(sql, slug, request.Title, request.Detail, request.DetailFormat, detailHTML, request.NewsImage,
+			request.PublishAt, request.ExpireAt, current.ID))
+		return err
+	})
+	if err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to update news"}
+	}
+	if err = setNewsTaxonomies(tx, response.ID, request); err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to update news tags"}
+	}
+	news := []NewsResponse{response}
+	if err = attachNewsTerms(tx, news); err == nil {
+		err = app.saveRequestRevision(tx, req, newsRevisions, response.ID)
+	}
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditUpdate, resourceType: AuditNews, resourceID: response.ID,
+			before: before[0], after: news[0]})
+	}
+	if err != nil {
+		return NewsResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to update news"}
+	}
+	return news[0], nil
+}
+
+// PublishNewsItem publishes a news item, immediately or at publish_at. Dates
+// left out of the request keep their current value.
+func (app *App) PublishNewsItem(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
+		return
+	}
+
+	var request PublishRequest
+	if len(reqBody) > 0 {
+		if err = unmarshalRequest(req, reqBody, &request); err != nil {
+			app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to convert json code")
+			return
+		}
+	}
+
+	sql := "UPDATE news_item SET status=$1, publish_at=$2, expire_at=$3, updated=now(), version=version+1 WHERE uid=$4 RETURNING " + newsColumns
+	app.updateNewsStatus(writer, req, sql, func(current NewsResponse) ([]interface{}, *ErrorResponse) {
+		publishAt, expireAt, err := publishSchedule(current, request, time.Now())
+		if err != nil {
+			return nil, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Expire date is wrong"}
+		}
+		return []interface{}{NewsPublished, publishAt, expireAt, current.ID}, nil
+	})
+}
+
+// publishSchedule returns the publish_at and expire_at of publishing current
+// at now. Dates the request leaves out keep their current value, so a
+// scheduled item stays scheduled, and an item without publish_at is published
+// now. The item must not expire before it is published or already be expired.
+func publishSchedule(current NewsResponse, request PublishRequest, now time.Time) (time.Time, *time.Time, error) {
+	publishAt := now
+	if request.PublishAt != nil {
+		publishAt = *request.PublishAt
+	} else if current.PublishAt != nil {
+		publishAt = *current.PublishAt
+	}
+	expireAt := current.ExpireAt
+	if request.ExpireAt != nil {
+		expireAt = request.ExpireAt
+	}
+	if expireAt != nil && !expireAt.After(publishAt) {
+		return publishAt, expireAt, fmt.Errorf("expire_at %s is not after publish_at %s", expireAt.Format(time.RFC3339), publishAt.Format(time.RFC3339))
+	}
+	if expireAt != nil && !expireAt.After(now) {
+		return publishAt, expireAt, fmt.Errorf("expire_at %s has passed, send a new one", expireAt.Format(time.RFC3339))
+	}
+	return publishAt, expireAt, nil
+}
+
+// UnpublishNewsItem moves a news item back to draft
+func (app *App) UnpublishNewsItem(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	sql := "UPDATE news_item SET status=$1, updated=now(), version=version+1 WHERE uid=$2 RETURNING " + newsColumns
+	app.updateNewsStatus(writer, req, sql, func(current NewsResponse) ([]interface{}, *ErrorResponse) {
+		return []interface{}{NewsDraft, current.ID}, nil
+	})
+}
+
+// ArchiveNewsItem archives a news item
+func (app *App) ArchiveNewsItem(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	sql := "UPDATE news_item SET status=$1, updated=now(), version=version+1 WHERE uid=$2 RETURNING " + newsColumns
+	app.updateNewsStatus(writer, req, sql, func(current NewsResponse) ([]interface{}, *ErrorResponse) {
+		return []interface{}{NewsArchived, current.ID}, nil
+	})
+}
+
+// updateNewsStatus runs a status update returning the news row and renders it.
+// args returns the parameters of sql for the locked current row.
+func (app *App) updateNewsStatus(writer http.ResponseWriter, req *http.Request, sql string,
+	args func(current NewsResponse) ([]interface{}, *ErrorResponse)) {
+	id := mux.Vars(req)["id"]
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news status")
+		return
+	}
+	defer tx.Rollback()
+
+	current, err := scanNews(tx.QueryRow("SELECT "+newsColumns+" FROM news_item WHERE uid=$1 AND deleted_at IS NULL FOR UPDATE", id))
+	if err == dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("News item [%s] not found", id))
+		return
+	}
 	if err != nil {
-		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete news")
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news status")
+		return
+	}
+	if !app.checkIfMatch(writer, req, current.Version, false) {
+		return
+	}
+	params, ex := args(current)
+	if ex != nil {
+		app.RenderError(writer, *ex)
+		return
+	}
+	response, err := scanNews(tx.QueryRow(sql, params...))
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news status")
+		return
+	}
+	news := []NewsResponse{current, response}
+	if err = attachNewsTerms(tx, news); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news status")
+		return
+	}
+	if err = app.saveRequestRevision(tx, req, newsRevisions, current.ID); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news status")
 		return
 	}
+	err = app.recordAudit(tx, req, auditEntry{action: AuditUpdate, resourceType: AuditNews, resourceID: current.ID,
+		before: news[0], after: news[1]})
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news status")
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news status")
+		return
+	}
+	writer.Header().Set("ETag", versionETag(news[1].Version, ""))
+	app.RenderJson(writer, http.StatusOK, news[1])
+}
 
-	rows.Next()
-	app.RenderJson(writer, http.StatusOK, "Delete news successful")
+// archiveExpiredNews archives published news items whose expire_at has passed,
+// recording a revision and an audit entry for each
+func (app *App) archiveExpiredNews(ctx context.Context) {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to archive expired news")
+		return
+	}
+	defer tx.Rollback()
 
+	sql := "SELECT uid FROM news_item WHERE status=$1 AND expire_at <= now() FOR UPDATE"
+	rows, err := tx.QueryContext(ctx, sql, NewsPublished)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to archive expired news")
+		return
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			logrus.WithError(err).Error("Failed to archive expired news")
+			return
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if err = archiveExpiredItem(tx, id); err != nil {
+			logrus.WithError(err).WithField("id", id).Error("Failed to archive expired news")
+			return
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		logrus.WithError(err).Error("Failed to archive expired news")
+		return
+	}
+	if len(ids) > 0 {
+		logrus.WithField("count", len(ids)).Info("Archived expired news items")
+	}
+}
+
+// archiveExpiredItem archives a locked expired news item
+func archiveExpiredItem(tx dbExecutor, id int) error {
+	before, err := rowSnapshot(tx, "news_item", id)
+	if err != nil {
+		return err
+	}
+	sql := "UPDATE news_item SET status=$1, updated=now(), version=version+1 WHERE uid=$2"
+	if _, err = tx.Exec(sql, NewsArchived, id); err != nil {
+		return err
+	}
+	if err = saveRevision(tx, newsRevisions, id, systemActor, ""); err != nil {
+		return err
+	}
+	after, err := rowSnapshot(tx, "news_item", id)
+	if err != nil {
+		return err
+	}
+	return insertAudit(tx, systemActor, "", auditEntry{action: AuditUpdate, resourceType: AuditNews, resourceID: id, before: before, after: after})
+}
+
+// DeleteNewsItem moves a news item to the trash and creates a json response of the data
+func (app *App) DeleteNewsItem(writer http.ResponseWriter, req *http.Request) {
+	if app.softDelete(writer, req, trashNews) {
+		app.RenderJson(writer, http.StatusOK, "Delete news successful")
+	}
 }
 
 // ValidateNews validates request
@@ -123,6 +601,21 @@ func (request *NewsRequest) ValidateNews() (int, string, error) {
 	if request.Detail == "" {
 		return http.StatusBadRequest, "Detail not null", fmt.Errorf("Detail is wrong")
 	}
+	if request.DetailFormat == "" {
+		request.DetailFormat = FormatText
+	}
+	if request.DetailFormat != FormatText && request.DetailFormat != FormatMarkdown && request.DetailFormat != FormatHTML {
+		return http.StatusBadRequest, "Detail format must be text, markdown or html", fmt.Errorf("Detail format is wrong")
+	}
+	if request.Status == "" {
+		request.Status = NewsDraft
+	}
+	if request.Status != NewsDraft && request.Status != NewsPublished && request.Status != NewsArchived {
+		return http.StatusBadRequest, "Status must be draft, published or archived", fmt.Errorf("Status is wrong")
+	}
+	if request.PublishAt != nil && request.ExpireAt != nil && !request.ExpireAt.After(*request.PublishAt) {
+		return http.StatusBadRequest, "Expire date must be after publish date", fmt.Errorf("Expire date is wrong")
+	}
 
 	return http.StatusOK, "", nil
 
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPublishSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		date := now.Add(time.Duration(hours) * time.Hour)
+		return &date
+	}
+	tests := []struct {
+		name        string
+		current     NewsResponse
+		request     PublishRequest
+		wantPublish time.Time
+		wantExpire  *time.Time
+		wantErr     string
+	}{
+		{"draft published now", NewsResponse{Status: NewsDraft}, PublishRequest{}, now, nil, ""},
+		{"scheduled draft keeps its date", NewsResponse{Status: NewsDraft, PublishAt: at(24)}, PublishRequest{}, *at(24), nil, ""},
+		{"published again keeps its date", NewsResponse{Status: NewsArchived, PublishAt: at(-24)}, PublishRequest{}, *at(-24), nil, ""},
+		{"request reschedules", NewsResponse{Status: NewsPublished, PublishAt: at(24)}, PublishRequest{PublishAt: at(48)}, *at(48), nil, ""},
+		{"request publishes a scheduled item now", NewsResponse{Status: NewsPublished, PublishAt: at(24)}, PublishRequest{PublishAt: &now}, now, nil, ""},
+		{"expire date kept", NewsResponse{Status: NewsDraft, ExpireAt: at(48)}, PublishRequest{}, now, at(48), ""},
+		{"expire date replaced", NewsResponse{Status: NewsDraft, ExpireAt: at(48)}, PublishRequest{ExpireAt: at(72)}, now, at(72), ""},
+		{"new date after the current expire date", NewsResponse{Status: NewsDraft, ExpireAt: at(48)}, PublishRequest{PublishAt: at(72)},
+			*at(72), at(48), "is not after publish_at"},
+		{"expire before publish", NewsResponse{Status: NewsDraft}, PublishRequest{PublishAt: at(48), ExpireAt: at(24)},
+			*at(48), at(24), "is not after publish_at"},
+		{"expire equals publish", NewsResponse{Status: NewsDraft}, PublishRequest{PublishAt: at(24), ExpireAt: at(24)},
+			*at(24), at(24), "is not after publish_at"},
+		{"expired item", NewsResponse{Status: NewsArchived, PublishAt: at(-72), ExpireAt: at(-24)}, PublishRequest{},
+			*at(-72), at(-24), "has passed"},
+		{"expired item with a new expire date", NewsResponse{Status: NewsArchived, PublishAt: at(-72), ExpireAt: at(-24)},
+			PublishRequest{ExpireAt: at(24)}, *at(-72), at(24), ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publishAt, expireAt, err := publishSchedule(test.current, test.request, now)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("got %v, want an error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !publishAt.Equal(test.wantPublish) {
+				t.Errorf("publish_at %v, want %v", publishAt, test.wantPublish)
+			}
+			if (expireAt == nil) != (test.wantExpire == nil) || (expireAt != nil && !expireAt.Equal(*test.wantExpire)) {
+				t.Errorf("expire_at %v, want %v", expireAt, test.wantExpire)
+			}
+		})
+	}
+}
+
+func TestValidateNewsSchedule(t *testing.T) {
+	publishAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	expireAt := publishAt.Add(time.Hour)
+	tests := []struct {
+		name    string
+		request NewsRequest
+		wantErr bool
+	}{
+		{"no schedule", NewsRequest{}, false},
+		{"publish only", NewsRequest{PublishAt: &publishAt}, false},
+		{"expire only", NewsRequest{ExpireAt: &expireAt}, false},
+		{"expire after publish", NewsRequest{PublishAt: &publishAt, ExpireAt: &expireAt}, false},
+		{"expire before publish", NewsRequest{PublishAt: &expireAt, ExpireAt: &publishAt}, true},
+		{"expire equals publish", NewsRequest{PublishAt: &publishAt, ExpireAt: &publishAt}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := test.request
+			request.Title, request.Detail = "Title", "Detail"
+			_, _, err := request.ValidateNews()
+			if (err != nil) != test.wantErr {
+				t.Errorf("got %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RelatedNewsResponse response struct
+//
+//swagger:response RelatedNewsResponse
+type RelatedNewsResponse struct {
+	ID        int        `json:"id"`
+	Slug      string     `json:"slug"`
+	Title     string     `json:"title"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// RelatedProjectResponse response struct
+//
+//swagger:response RelatedProjectResponse
+type RelatedProjectResponse struct {
+	ID          int    `json:"id"`
+	Slug        string `json:"slug"`
+	ProjectName string `json:"project_name"`
+	Status      string `json:"status"`
+}
+
+// relatedNews loads the news items linked to a project, newest first.
+// Anonymous callers only get visible news items.
+func (app *App) relatedNews(projectID int, locale string, visibleOnly bool) ([]RelatedNewsResponse, error) {
+	var where whereBuilder
+	from := newsFrom(&where, locale) + " JOIN news_project np ON np.news_uid=n.uid"
+	where.Add("np.project_uid=?", projectID)
+	if visibleOnly {
+		where.Add(newsVisibleSQL)
+	} else {
+		where.Add(newsLiveSQL)
+	}
+	sql := "SELECT n.uid,n.slug,COALESCE(t.news_title,n.news_title),n.status,n.publish_at FROM " + from + where.SQL() +
+		" ORDER BY COALESCE(n.publish_at,n.created) DESC"
+	rows, err := app.db.Query(sql, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	news := []RelatedNewsResponse{}
+	for rows.Next() {
+		item := RelatedNewsResponse{}
+		var publishAt dbsql.NullTime
+		if err = rows.Scan(&item.ID, &item.Slug, &item.Title, &item.Status, &publishAt); err != nil {
+			return nil, err
+		}
+		if publishAt.Valid {
+			item.PublishAt = &publishAt.Time
+		}
+		news = append(news, item)
+	}
+	return news, rows.Err()
+}
+
+// relatedProjects loads the projects linked to a news item, latest started first
+func (app *App) relatedProjects(newsID int, locale string) ([]RelatedProjectResponse, error) {
+	var where whereBuilder
+	from := projectFrom(&where, locale) + " JOIN news_project np ON np.project_uid=p.uid"
+	where.Add("np.news_uid=?", newsID)
+	where.Add(projectLiveSQL)
+	sql := "SELECT p.uid,p.slug,COALESCE(t.project_name,p.project_name)," + projectStatusExpr(&where) + " FROM " + from + where.SQL() +
+		" ORDER BY p.start_date DESC NULLS LAST, p.uid DESC"
+	rows, err := app.db.Query(sql, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []RelatedProjectResponse{}
+	for rows.Next() {
+		project := RelatedProjectResponse{}
+		if err = rows.Scan(&project.ID, &project.Slug, &project.ProjectName, &project.Status); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	return projects, rows.Err()
+}
+
+// LinkProjectToNews links a project to a news item
+func (app *App) LinkProjectToNews(writer http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	app.linkNewsProject(writer, req, params["id"], params["project_id"])
+}
+
+// UnlinkProjectFromNews removes the link between a news item and a project
+func (app *App) UnlinkProjectFromNews(writer http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	app.unlinkNewsProject(writer, req, params["id"], params["project_id"])
+}
+
+// LinkNewsToProject links a news item to a project
+func (app *App) LinkNewsToProject(writer http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	app.linkNewsProject(writer, req, params["news_id"], params["id"])
+}
+
+// UnlinkNewsFromProject removes the link between a project and a news item
+func (app *App) UnlinkNewsFromProject(writer http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	app.unlinkNewsProject(writer, req, params["news_id"], params["id"])
+}
+
+func (app *App) linkNewsProject(writer http.ResponseWriter, req *http.Request, newsID string, projectID string) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to link news item and project")
+		return
+	}
+	defer tx.Rollback()
+
+	var newsExists, projectExists bool
+	sql := "SELECT EXISTS(SELECT 1 FROM news_item WHERE uid=$1 AND deleted_at IS NULL), " +
+		"EXISTS(SELECT 1 FROM project WHERE uid=$2 AND deleted_at IS NULL)"
+	if err = tx.QueryRow(sql, newsID, projectID).Scan(&newsExists, &projectExists); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to link news item and project")
+		return
+	}
+	if !newsExists {
+		app.RenderErrorResponse(writer, http.StatusNotFound, NotFoundError, fmt.Sprintf("News item [%s] not found", newsID))
+		return
+	}
+	if !projectExists {
+		app.RenderErrorResponse(writer, http.StatusNotFound, NotFoundError, fmt.Sprintf("Project [%s] not found", projectID))
+		return
+	}
+
+	sql = "INSERT INTO news_project(news_uid,project_uid,created) VALUES($1,$2,$3) ON CONFLICT DO NOTHING"
+	result, err := tx.Exec(sql, newsID, projectID, time.Now())
+	if err == nil {
+		if count, _ := result.RowsAffected(); count > 0 {
+			err = bumpLinkedVersions(tx, newsID, projectID)
+			if err == nil {
+				err = app.recordAudit(tx, req, auditEntry{action: AuditCreate, resourceType: AuditNewsProject, resourceID: newsID + "/" + projectID,
+					after: map[string]string{"news_id": newsID, "project_id": projectID}})
+			}
+		}
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to link news item and project")
+		return
+	}
+	app.RenderJson(writer, http.StatusOK, nil)
+}
+
+func (app *App) unlinkNewsProject(writer http.ResponseWriter, req *http.Request, newsID string, projectID string) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to unlink news item and project")
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM news_project WHERE news_uid=$1 AND project_uid=$2", newsID, projectID)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to unlink news item and project")
+		return
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		app.RenderErrorResponse(writer, http.StatusNotFound, NotFoundError,
+			fmt.Sprintf("News item [%s] is not linked to project [%s]", newsID, projectID))
+		return
+	}
+	err = bumpLinkedVersions(tx, newsID, projectID)
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditDelete, resourceType: AuditNewsProject, resourceID: newsID + "/" + projectID,
+			before: map[string]string{"news_id": newsID, "project_id": projectID}})
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to unlink news item and project")
+		return
+	}
+	app.RenderJson(writer, http.StatusOK, nil)
+}
+
+// bumpLinkedVersions changes the versions of both sides of a link, since their
+// related items are part of their representation
+func bumpLinkedVersions(db dbExecutor, newsID string, projectID string) error {
+	if err := bumpVersion(db, "news_item", newsID); err != nil {
+		return err
+	}
+	return bumpVersion(db, "project", projectID)
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationConfig is config struct for email notifications
+type NotificationConfig struct {
+	Enabled bool   `yaml:"enabled" envconfig:"NOTIFICATION_ENABLED"`
+	From    string `yaml:"from" envconfig:"NOTIFICATION_FROM"`
+	// directory containing hiring_<department>.tmpl, hiring.tmpl and applicant.tmpl overrides
+	TemplateDir string `yaml:"template_dir" envconfig:"NOTIFICATION_TEMPLATE_DIR"`
+	// hiring team addresses by department, "default" is used for unknown departments
+	HiringTeam map[string][]string `yaml:"hiring_team"`
+	// number of delivery attempts before an email is marked as failed
+	MaxAttempts int `yaml:"max_attempts"`
+	// how often the outbox is polled for pending emails
+	PollInterval time.Duration `yaml:"poll_interval"`
+	SMTP         SMTPConfig    `yaml:"smtp"`
+}
+
+// SMTPConfig is config struct for the smtp server
+type SMTPConfig struct {
+	Host         string `yaml:"host" envconfig:"SMTP_HOST"`
+	Port         int    `yaml:"port" envconfig:"SMTP_PORT"`
+	Username     string `yaml:"username" envconfig:"SMTP_USERNAME"`
+	Password     string `yaml:"password" envconfig:"SMTP_PASSWORD" secret:"true"`
+	PasswordFile string `yaml:"password_file" envconfig:"SMTP_PASSWORD_FILE"`
+}
+
+// Email is a single outgoing message
+type Email struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails
+type Mailer interface {
+	Send(email Email) error
+}
+
+// SMTPMailer sends emails through an smtp server.
+// Authentication is skipped when no username is configured, so a local smtp
+// stand-in (MailHog, smtp4dev, ...) can be used during development and tests.
+type SMTPMailer struct {
+	conf SMTPConfig
+}
+
+// NewSMTPMailer creates a new smtp mailer
+func NewSMTPMailer(conf SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{conf: conf}
+}
+
+// Send sends the email
+func (m *SMTPMailer) Send(email Email) error {
+	addr := net.JoinHostPort(m.conf.Host, strconv.Itoa(m.conf.Port))
+	var auth smtp.Auth
+	if m.conf.Username != "" {
+		auth = smtp.PlainAuth("", m.conf.Username, m.conf.Password, m.conf.Host)
+	}
+	message, err := email.Message()
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(addr, auth, email.From, email.To, message)
+}
+
+// Message builds the RFC 5322 representation of the email
+func (email Email) Message() ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", email.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(email.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(&buf)
+	if _, err := writer.Write([]byte(email.Body)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+const defaultHiringTemplate = `{{define "subject"}}New job application: {{.FirstName}} {{.LastName}} ({{.Department}}){{end}}
+{{define "body"}}A new job application was received.
+
+Application : #{{.ID}}
+Name        : {{.FirstName}} {{.LastName}}
+Email       : {{.Email}}
+Phone       : {{.PhoneNumber}}
+Department  : {{.Department}}
+
+{{.CvMessage}}
+{{end}}`
+
+const defaultApplicantTemplate = `{{define "subject"}}We received your application{{end}}
+{{define "body"}}Dear {{.FirstName}} {{.LastName}},
+
+Thank you for applying to our {{.Department}} team. We have received your
+application and will get back to you as soon as possible.
+{{end}}`
+
+// JobApplicationEmail is the data passed to job application email templates
+type JobApplicationEmail struct {
+	ID int
+	JobRequest
+}
+
+// Notifier renders notification emails into the outbox and delivers them
+// asynchronously, so a mail outage never fails the request that caused it.
+type Notifier struct {
+	conf   NotificationConfig
+	db     *sql.DB
+	mailer Mailer
+}
+
+// NewNotifier creates a new notifier
+func NewNotifier(conf NotificationConfig, db *sql.DB, mailer Mailer) *Notifier {
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = 5
+	}
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = 30 * time.Second
+	}
+	return &Notifier{conf: conf, db: db, mailer: mailer}
+}
+
+// loadTemplate loads the first existing template from the template directory,
+// falling back to the built in template.
+func (n *Notifier) loadTemplate(fallback string, names ...string) (*template.Template, error) {
+	if n.conf.TemplateDir != "" {
+		for _, name := range names {
+			path := filepath.Join(n.conf.TemplateDir, name)
+			if _, err := os.Stat(path); err == nil {
+				return template.ParseFiles(path)
+			}
+		}
+	}
+	return template.New("default").Parse(fallback)
+}
+
+// render executes the subject and body blocks of a template
+func render(tmpl *template.Template, data interface{}) (string, string, error) {
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
+
+// hiringTeam returns the recipients for a department
+func (n *Notifier) hiringTeam(department string) []string {
+	if recipients, ok := n.conf.HiringTeam[strings.ToLower(department)]; ok {
+		return recipients
+	}
+	return n.conf.HiringTeam["default"]
+}
+
+// JobApplicationReceived queues the hiring team notification and the
+// applicant acknowledgement for a new job application.
+func (n *Notifier) JobApplicationReceived(db dbExecutor, id int, request JobRequest) error {
+	if !n.conf.Enabled {
+		return nil
+	}
+	data := JobApplicationEmail{ID: id, JobRequest: request}
+	department := strings.ToLower(request.Department)
+
+	hiring, err := n.loadTemplate(defaultHiringTemplate, "hiring_"+department+".tmpl", "hiring.tmpl")
+	if err != nil {
+		return err
+	}
+	if recipients := n.hiringTeam(department); len(recipients) > 0 {
+		subject, body, err := render(hiring, data)
+		if err != nil {
+			return err
+		}
+		if err = n.Enqueue(db, recipients, subject, body); err != nil {
+			return err
+		}
+	}
+
+	applicant, err := n.loadTemplate(defaultApplicantTemplate, "applicant.tmpl")
+	if err != nil {
+		return err
+	}
+	subject, body, err := render(applicant, data)
+	if err != nil {
+		return err
+	}
+	return n.Enqueue(db, []string{request.Email}, subject, body)
+}
+
+// Enqueue stores an email in the outbox. Passing a transaction makes the
+// email part of the surrounding write.
+func (n *Notifier) Enqueue(db dbExecutor, recipients []string, subject string, body string) error {
+	sql := "INSERT INTO notification_outbox(recipients,subject,body,next_attempt_at,created) VALUES($1,$2,$3,$4,$4)"
+	_, err := db.Exec(sql, strings.Join(recipients, ","), subject, body, time.Now())
+	return err
+}
+
+// outboxItem is a pending email in the outbox
+type outboxItem struct {
+	ID         int
+	Recipients string
+	Subject    string
+	Body       string
+	Attempts   int
+}
+
+// outboxLease is how long claimed emails are held by a worker. Emails of a
+// worker that dies while sending are picked up again after it.
+const outboxLease = 10 * time.Minute
+
+// ProcessOutbox delivers due emails from the outbox. Failed deliveries are
+// retried with exponential backoff until MaxAttempts is reached.
+func (n *Notifier) ProcessOutbox(ctx context.Context) {
+	items, err := n.claimOutbox(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to fetch outbox")
+		return
+	}
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		n.deliver(n.db, item)
+	}
+}
+
+// claimOutbox leases due emails to this worker by moving their next attempt
+// past the lease. The claim is a single statement, so no rows stay locked
+// while emails are sent.
+func (n *Notifier) claimOutbox(ctx context.Context) ([]outboxItem, error) {
+	now := time.Now()
+	sql := "UPDATE notification_outbox SET next_attempt_at=$2 WHERE id IN (" +
+		"SELECT id FROM notification_outbox WHERE sent_at IS NULL AND failed_at IS NULL AND next_attempt_at <= $1 " +
+		"ORDER BY id LIMIT 50 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING id,recipients,subject,body,attempts"
+	rows, err := n.db.QueryContext(ctx, sql, now, now.Add(outboxLease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []outboxItem
+	for rows.Next() {
+		item := outboxItem{}
+		if err = rows.Scan(&item.ID, &item.Recipients, &item.Subject, &item.Body, &item.Attempts); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// deliver sends a claimed email and records the outcome in its own statement,
+// so one failed update does not affect the other emails
+func (n *Notifier) deliver(db dbExecutor, item outboxItem) {
+	email := Email{From: n.conf.From, To: strings.Split(item.Recipients, ","), Subject: item.Subject, Body: item.Body}
+	if err := n.mailer.Send(email); err != nil {
+		n.markFailedAttempt(db, item, err)
+		return
+	}
+	if _, err := db.Exec("UPDATE notification_outbox SET sent_at=$1, attempts=attempts+1 WHERE id=$2", time.Now(), item.ID); err != nil {
+		logrus.WithError(err).WithField("id", item.ID).Error("Failed to mark email as sent")
+	}
+}
+
+// markFailedAttempt records a failed delivery and schedules the next attempt
+func (n *Notifier) markFailedAttempt(db dbExecutor, item outboxItem, sendErr error) {
+	attempts := item.Attempts + 1
+	logger := logrus.WithError(sendErr).WithFields(logrus.Fields{"id": item.ID, "attempts": attempts})
+
+	var err error
+	if attempts >= n.conf.MaxAttempts {
+		logger.Error("Giving up sending email")
+		_, err = db.Exec("UPDATE notification_outbox SET attempts=$1, last_error=$2, failed_at=$3 WHERE id=$4",
+			attempts, sendErr.Error(), time.Now(), item.ID)
+	} else {
+		logger.Warn("Failed to send email, will retry")
+		backoff := n.conf.PollInterval * time.Duration(1<<uint(attempts))
+		_, err = db.Exec("UPDATE notification_outbox SET attempts=$1, last_error=$2, next_attempt_at=$3 WHERE id=$4",
+			attempts, sendErr.Error(), time.Now().Add(backoff), item.ID)
+	}
+	if err != nil {
+		logger.WithField("updateError", err).Error("Failed to update outbox")
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeMailer records the emails it is asked to send
+type fakeMailer struct {
+	sent []Email
+	err  error
+}
+
+func (m *fakeMailer) Send(email Email) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+// recordingExecutor records the statements run through Exec
+type recordingExecutor struct {
+	queries []string
+	args    [][]interface{}
+}
+
+func (e *recordingExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	e.args = append(e.args, args)
+	return nil, nil
+}
+
+func (e *recordingExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (e *recordingExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func TestJobApplicationReceivedQueuesEmails(t *testing.T) {
+	notifier := NewNotifier(NotificationConfig{
+		Enabled:    true,
+		HiringTeam: map[string][]string{"default": {"hr@codonex.com"}, "engineering": {"eng@codonex.com", "cto@codonex.com"}},
+	}, nil, &fakeMailer{})
+	db := &recordingExecutor{}
+	request := JobRequest{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Department: "Engineering"}
+	if err := notifier.JobApplicationReceived(db, 7, request); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(db.args) != 2 {
+		t.Fatalf("queued %d emails, want 2", len(db.args))
+	}
+	if recipients := db.args[0][0]; recipients != "eng@codonex.com,cto@codonex.com" {
+		t.Errorf("hiring email goes to %v", recipients)
+	}
+	if subject := db.args[0][1]; subject != "New job application: Ada Lovelace (Engineering)" {
+		t.Errorf("hiring subject is %q", subject)
+	}
+	if recipients := db.args[1][0]; recipients != "ada@example.com" {
+		t.Errorf("applicant email goes to %v", recipients)
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	item := outboxItem{ID: 3, Recipients: "a@codonex.com,b@codonex.com", Subject: "Hello", Body: "Body", Attempts: 1}
+	tests := []struct {
+		name    string
+		sendErr error
+		item    outboxItem
+		update  string
+	}{
+		{"sent", nil, item, "sent_at=$1"},
+		{"retried", errors.New("connection refused"), item, "next_attempt_at=$3"},
+		{"given up", errors.New("connection refused"), outboxItem{ID: 3, Attempts: 4}, "failed_at=$3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mailer := &fakeMailer{err: test.sendErr}
+			notifier := NewNotifier(NotificationConfig{From: "Cerci <no-reply@codonex.com>", MaxAttempts: 5}, nil, mailer)
+			db := &recordingExecutor{}
+			notifier.deliver(db, test.item)
+
+			if len(db.queries) != 1 || !strings.Contains(db.queries[0], test.update) {
+				t.Fatalf("updates %v, want one setting %s", db.queries, test.update)
+			}
+			if test.sendErr != nil {
+				if attempts := db.args[0][0]; attempts != test.item.Attempts+1 {
+					t.Errorf("attempts is %v, want %d", attempts, test.item.Attempts+1)
+				}
+				return
+			}
+			if len(mailer.sent) != 1 || len(mailer.sent[0].To) != 2 || mailer.sent[0].From != "Cerci <no-reply@codonex.com>" {
+				t.Errorf("sent %+v", mailer.sent)
+			}
+		})
+	}
+}
+
+// startSMTPStandIn serves a single smtp session on a local port and sends
+// the received message on the returned channel
+func startSMTPStandIn(t *testing.T) (SMTPConfig, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.Fields(line + " x")[0]); command {
+			case "EHLO", "HELO", "MAIL", "RCPT", "RSET", "NOOP":
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err = reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{Host: addr.IP.String(), Port: addr.Port}, received
+}
+
+func TestSMTPMailerSendsToStandIn(t *testing.T) {
+	conf, received := startSMTPStandIn(t)
+	mailer := NewSMTPMailer(conf)
+	email := Email{From: "no-reply@codonex.com", To: []string{"hr@codonex.com"}, Subject: "Başvuru", Body: "Merhaba"}
+	if err := mailer.Send(email); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case message := <-received:
+		for _, want := range []string{"To: hr@codonex.com", "Subject: =?utf-8?q?Ba=C5=9Fvuru?=", "Merhaba"} {
+			if !strings.Contains(message, want) {
+				t.Errorf("message is missing %q:\n%s", want, message)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stand-in received no message")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestOpenAPISpecCoversRoutes fails when a route of AddRoutes is missing from
+// the generated spec, or the spec describes a route that no longer exists.
+// Run go generate to update the spec.
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(openAPISpec), &spec); err != nil {
+		t.Fatalf("openAPISpec is not valid JSON: %v", err)
+	}
+
+	app := &App{Router: mux.NewRouter()}
+	app.AddRoutes()
+	registered := map[string]bool{}
+	err := app.Router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		path := muxPathParam.ReplaceAllString(template, "{$1}")
+		for _, method := range methods {
+			operation := strings.ToLower(method) + " " + path
+			registered[operation] = true
+			if _, ok := spec.Paths[path][strings.ToLower(method)]; !ok {
+				t.Errorf("%s %s is missing from the OpenAPI spec, run go generate", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is in the OpenAPI spec but not a route, run go generate", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// TestAPIDocsPagePinsSwaggerUI keeps the docs page on one exact Swagger UI
+// release, a moving tag would let the CDN change the code it serves.
+func TestAPIDocsPagePinsSwaggerUI(t *testing.T) {
+	if !regexp.MustCompile(`@\d+\.\d+\.\d+/$`).MatchString(swaggerUIDist) {
+		t.Errorf("swaggerUIDist %s is not pinned to an exact version", swaggerUIDist)
+	}
+	if n := strings.Count(apiDocsPage, swaggerUIDist); n != 2 {
+		t.Errorf("apiDocsPage loads %d assets from %s, want 2", n, swaggerUIDist)
+	}
+}
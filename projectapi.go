@@ -1,10 +1,12 @@
 package main
 
 import (
-	"encoding/json"
+	dbsql "database/sql"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -18,22 +20,142 @@ type ProjectRequest struct {
 	ProjectImages [][]byte  `json:"project_images"`
 	StartDate     time.Time `json:"start_date"`
 	FinishDate    time.Time `json:"finish_date"`
+	// optional structured fields, left untouched on update when omitted
+	Client       *ClientRequest   `json:"client,omitempty"`
+	Location     *LocationRequest `json:"location,omitempty"`
+	Technologies []string         `json:"technologies,omitempty"`
+	Team         []TeamMember     `json:"team,omitempty"`
 }
 
 // ProjectResponse response struct
 //swagger:response ProjectResponse
 type ProjectResponse struct {
-	ID            int       `json:"id"`
-	ProjectName   string    `json:"project_name"`
-	Detail        string    `json:"detail"`
-	ProjectImages [][]byte  `json:"project_images"`
-	StartDate     time.Time `json:"start_date"`
-	FinishDate    time.Time `json:"finish_date"`
+	ID            int               `json:"id"`
+	Slug          string            `json:"slug"`
+	ProjectName   string            `json:"project_name"`
+	Detail        string            `json:"detail"`
+	ProjectImages [][]byte          `json:"project_images"`
+	StartDate     time.Time         `json:"start_date"`
+	FinishDate    time.Time         `json:"finish_date"`
+	Status        string            `json:"status"`
+	Locale        string            `json:"locale"`
+	Version       int               `json:"version"`
+	Client        *ClientResponse   `json:"client,omitempty"`
+	Location      *LocationResponse `json:"location,omitempty"`
+	Technologies  []TermResponse    `json:"technologies"`
+	Team          []TeamMember      `json:"team"`
+	// only filled when a single project is requested
+	RelatedNews []RelatedNewsResponse `json:"related_news,omitempty"`
+}
+
+// TimelineYearResponse response struct
+//swagger:response TimelineYearResponse
+type TimelineYearResponse struct {
+	Year     int               `json:"year"`
+	Projects []ProjectResponse `json:"projects"`
+}
+
+// Project statuses, derived from the start and finish dates
+const (
+	ProjectPlanned   = "planned"
+	ProjectOngoing   = "ongoing"
+	ProjectCompleted = "completed"
+)
+
+// projectStatusSQL computes the status of a project (p) on the date ? the
+// same way as ProjectStatus, see projectStatusExpr. Missing dates are stored as
+// NULL by nullDate, where ProjectStatus sees a zero time.
+const projectStatusSQL = "(CASE WHEN p.finish_date < ?::date THEN 'completed' " +
+	"WHEN p.start_date IS NULL OR p.start_date > ?::date THEN 'planned' ELSE 'ongoing' END)"
+
+// projectStatusExpr returns projectStatusSQL for today, bound as an argument of where
+func projectStatusExpr(where *whereBuilder) string {
+	return strings.Replace(projectStatusSQL, "?", where.Arg(projectToday().Format(dateLayout)), -1)
+}
+
+// projectToday returns the date project statuses are computed for, the date
+// of the server. The database is given the same date instead of using its own
+// CURRENT_DATE, so that filters and reported statuses agree.
+func projectToday() time.Time {
+	return dateOf(time.Now())
+}
+
+// dateOf returns the calendar date of t as midnight UTC, like the dates read
+// from the database
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nullDate returns t for a date column, NULL when t is zero so that a missing
+// date is not stored as 0001-01-01
+func nullDate(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// projectLiveSQL matches projects (p) that are not in the trash
+const projectLiveSQL = "p.deleted_at IS NULL"
+
+const dateLayout = "2006-01-02"
+
+const projectColumns = "uid,slug,project_name,detail,project_images,start_date,finish_date,version"
+
+// projectLocalizedColumns selects the same columns as projectColumns from
+// projectFrom, preferring the translated name and detail
+const projectLocalizedColumns = "p.uid,p.slug,COALESCE(t.project_name,p.project_name),COALESCE(t.detail,p.detail)," +
+	"p.project_images,p.start_date,p.finish_date,p.version"
+
+// scanProject reads a project row selected with projectColumns, extra receives
+// any additional selected columns
+func scanProject(row rowScanner, extra ...interface{}) (ProjectResponse, error) {
+	u := ProjectResponse{}
+	var start, finish dbsql.NullTime
+	dest := []interface{}{&u.ID, &u.Slug, &u.ProjectName, &u.Detail, &u.ProjectImages, &start, &finish, &u.Version}
+	err := row.Scan(append(dest, extra...)...)
+	u.StartDate, u.FinishDate = start.Time, finish.Time
+	u.Status = ProjectStatus(u.StartDate, u.FinishDate, projectToday())
+	return u, err
+}
+
+// projectFrom returns the FROM clause joining project (p) with its translation (t) for locale
+func projectFrom(where *whereBuilder, locale string) string {
+	return "project p LEFT JOIN project_translation t ON t.project_uid=p.uid AND t.locale=" + where.Arg(locale)
+}
+
+// queryLocalizedProjects runs a localized project query and returns the project items
+func (app *App) queryLocalizedProjects(where *whereBuilder, from string, suffix string) ([]ProjectResponse, error) {
+	sql := "SELECT " + projectLocalizedColumns + ",t.locale FROM " + from + where.SQL() + suffix
+	rows, err := app.db.Query(sql, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []ProjectResponse
+	for rows.Next() {
+		var translated dbsql.NullString
+		u, err := scanProject(rows, &translated)
+		if err != nil {
+			return nil, err
+		}
+		u.Locale = app.resolvedLocale(translated)
+		projects = append(projects, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	return projects, attachProjectMetadata(app.db, projects)
 }
 
 // AddProjectItem add new ProjectItem to database and creates a json response of the data
 func (app *App) AddProjectItem(writer http.ResponseWriter, req *http.Request) {
-
+	if !app.requireEditor(writer, req) {
+		return
+	}
 	reqBody, err := ioutil.ReadAll(req.Body)
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
@@ -41,7 +163,7 @@ func (app *App) AddProjectItem(writer http.ResponseWriter, req *http.Request) {
 	}
 
 	var request ProjectRequest
-	err = json.Unmarshal(reqBody, &request)
+	err = unmarshalRequest(req, reqBody, &request)
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed convert JSON")
 		return
@@ -53,78 +175,318 @@ func (app *App) AddProjectItem(writer http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	sql := fmt.Sprint("INSERT INTO project(project_name,detail,project_images,start_date,finish_date,created) VALUES($1,$2,$3,$4,$5,$6) returning uid;")
-	var lastInsertId int
-	err = app.db.QueryRow(sql, request.ProjectName,
-		request.Detail,
-		request.ProjectImages,
-		request.StartDate,
-		request.FinishDate,
-		time.Now(),
-	).Scan(&lastInsertId)
-
+	tx, err := app.db.Begin()
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to create project")
 		return
-	} else {
-		app.RenderJson(writer, http.StatusOK, ProjectResponse{ProjectName: request.ProjectName, Detail: request.Detail, ProjectImages: request.ProjectImages, ID: lastInsertId})
 	}
+	defer tx.Rollback()
+
+	response, ex := app.createProject(tx, req, request)
+	if ex != nil {
+		app.RenderError(writer, *ex)
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to create project")
+		return
+	}
+	writer.Header().Set("ETag", versionETag(response.Version, ""))
+	app.RenderJson(writer, http.StatusOK, response)
+}
+
+// createProject inserts a validated project item with its metadata, first
+// revision and audit entry
+func (app *App) createProject(tx dbExecutor, req *http.Request, request ProjectRequest) (ProjectResponse, *ErrorResponse) {
+	sql := fmt.Sprint("INSERT INTO project(slug,project_name,detail,project_images,start_date,finish_date,created) VALUES($1,$2,$3,$4,$5,$6,$7) returning uid;")
+	var lastInsertId int
+	slug, err := writeWithSlug(tx, func() (string, error) {
+		return uniqueSlug(tx, SlugProject, request.ProjectName, 0)
+	}, func(slug string) error {
+		return tx.QueryRow(sql, slug,
+			request.ProjectName,
+			request.Detail,
+			request.ProjectImages,
+			nullDate(request.StartDate),
+			nullDate(request.FinishDate),
+			time.Now(),
+		).Scan(&lastInsertId)
+	})
+
+	if err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to create project"}
+	}
+	if err = saveProjectMetadata(tx, lastInsertId, request); err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to save project metadata"}
+	}
+	response := []ProjectResponse{{
+		ID:            lastInsertId,
+		Slug:          slug,
+		ProjectName:   request.ProjectName,
+		Detail:        request.Detail,
+		ProjectImages: request.ProjectImages,
+		StartDate:     request.StartDate,
+		FinishDate:    request.FinishDate,
+		Status:        ProjectStatus(request.StartDate, request.FinishDate, projectToday()),
+		Locale:        app.defaultLanguage(),
+		Version:       1,
+	}}
+	if err = attachProjectMetadata(tx, response); err == nil {
+		err = app.saveRequestRevision(tx, req, projectRevisions, lastInsertId)
+	}
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditCreate, resourceType: AuditProject, resourceID: lastInsertId, after: response[0]})
+	}
+	if err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to create project"}
+	}
+	return response[0], nil
 }
 
-// GetProjectItems fetches all project items from database and creates a json response of the data
+// GetProjectItems fetches project items from database and creates a json response of the data.
+// Supported filters: active_on, started_from, started_to, finishing_from, finishing_to (YYYY-MM-DD), status,
+// client, technology (repeatable), member, role, city, country and near=lat,lng with radius in km.
 func (app *App) GetProjectItems(writer http.ResponseWriter, req *http.Request) {
-	params := mux.Vars(req)
+	locale := app.requestLocale(req)
+	var where whereBuilder
+	from := projectFrom(&where, locale)
+	where.Add(projectLiveSQL)
+	if err := addProjectFilters(&where, req.URL.Query()); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Invalid project filter")
+		return
+	}
 
-	sql := fmt.Sprintf("SELECT uid,project_name,detail,project_images,start_date,finish_date FROM where start_date >= $1 and finish_date < $2 project")
-	rows, err := app.db.Query(sql, params["start_date"], "finish_date")
+	projects, err := app.queryLocalizedProjects(&where, from, " ORDER BY p.start_date DESC NULLS LAST, p.uid DESC")
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get projects")
 		return
 	}
+	setContentLanguage(writer, locale)
+	app.RenderJson(writer, http.StatusOK, projects)
+}
 
-	var project []ProjectResponse
-	for rows.Next() {
-		u := ProjectResponse{}
-		rows.Scan(&u.ID, &u.ProjectName, &u.Detail, &u.ProjectImages, &u.StartDate, &u.FinishDate)
-		project = append(project, u)
+// GetProjectTimeline returns the projects grouped by the year they started in.
+// Projects without a start date are left out. It accepts the same filters as GetProjectItems.
+func (app *App) GetProjectTimeline(writer http.ResponseWriter, req *http.Request) {
+	locale := app.requestLocale(req)
+	var where whereBuilder
+	from := projectFrom(&where, locale)
+	where.Add(projectLiveSQL)
+	if err := addProjectFilters(&where, req.URL.Query()); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Invalid project filter")
+		return
+	}
+	where.Add("p.start_date IS NOT NULL")
+
+	projects, err := app.queryLocalizedProjects(&where, from, " ORDER BY p.start_date DESC, p.uid DESC")
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get project timeline")
+		return
 	}
-	app.RenderJson(writer, http.StatusOK, project)
+
+	timeline := []TimelineYearResponse{}
+	for _, project := range projects {
+		year := project.StartDate.Year()
+		if len(timeline) == 0 || timeline[len(timeline)-1].Year != year {
+			timeline = append(timeline, TimelineYearResponse{Year: year})
+		}
+		last := &timeline[len(timeline)-1]
+		last.Projects = append(last.Projects, project)
+	}
+	setContentLanguage(writer, locale)
+	app.RenderJson(writer, http.StatusOK, timeline)
+}
+
+// addProjectFilters adds the list query string filters to where
+func addProjectFilters(where *whereBuilder, query url.Values) error {
+	dateFilters := []struct {
+		param     string
+		condition string
+	}{
+		{"active_on", "p.start_date <= ? AND (p.finish_date IS NULL OR p.finish_date >= ?)"},
+		{"started_from", "p.start_date >= ?"},
+		{"started_to", "p.start_date <= ?"},
+		{"finishing_from", "p.finish_date >= ?"},
+		{"finishing_to", "p.finish_date <= ?"},
+	}
+	for _, filter := range dateFilters {
+		value := query.Get(filter.param)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return fmt.Errorf("%s must be a date formatted as YYYY-MM-DD", filter.param)
+		}
+		where.Add(filter.condition, date, date)
+	}
+
+	if status := query.Get("status"); status != "" {
+		if status != ProjectPlanned && status != ProjectOngoing && status != ProjectCompleted {
+			return fmt.Errorf("status must be planned, ongoing or completed")
+		}
+		where.Add(projectStatusExpr(where)+"=?", status)
+	}
+	return addProjectMetadataFilters(where, query)
+}
+
+// ProjectStatus derives the status of a project from its dates on the date today
+func ProjectStatus(start time.Time, finish time.Time, today time.Time) string {
+	today = dateOf(today)
+	if !finish.IsZero() && dateOf(finish).Before(today) {
+		return ProjectCompleted
+	}
+	if start.IsZero() || dateOf(start).After(today) {
+		return ProjectPlanned
+	}
+	return ProjectOngoing
 }
 
 // FindProjectItem finds project item from database and creates a json response of the data
 func (app *App) FindProjectItem(writer http.ResponseWriter, req *http.Request) {
 	params := mux.Vars(req)
-	sql := fmt.Sprint("SELECT uid,project_name,detail,project_images,start_date,finish_date FROM project WHERE uid=$1")
-	rows, err := app.db.Query(sql, params["id"])
+	locale := app.requestLocale(req)
+	var where whereBuilder
+	from := projectFrom(&where, locale)
+	where.Add(projectLiveSQL)
+	where.Add("p.uid=?", params["id"])
 
-	if err != nil {
-		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed find project")
+	projects, err := app.queryLocalizedProjects(&where, from, "")
+	if err != nil || len(projects) == 0 {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Project [%s] not found", params["id"]))
 		return
 	}
-	u := ProjectResponse{}
-	for rows.Next() {
-		err = rows.Scan(&u.ID, &u.ProjectName, &u.Detail, &u.ProjectImages, &u.StartDate, &u.FinishDate)
+	visibleOnly := !app.authenticate(req).IsEditor()
+	if projects[0].RelatedNews, err = app.relatedNews(projects[0].ID, locale, visibleOnly); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get related news")
+		return
 	}
-	if err != nil || u.ID == 0 {
-		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Project [%s] not found", params["id"]))
+	setContentLanguage(writer, locale)
+	app.renderVersioned(writer, req, versionETag(projects[0].Version, locale), projects[0])
+}
+
+// FindProjectItemBySlug finds project item by its slug. Old slugs redirect to the current one.
+func (app *App) FindProjectItemBySlug(writer http.ResponseWriter, req *http.Request) {
+	slug := mux.Vars(req)["slug"]
+	locale := app.requestLocale(req)
+	var where whereBuilder
+	from := projectFrom(&where, locale)
+	where.Add(projectLiveSQL)
+	where.Add("p.slug=?", slug)
+
+	projects, err := app.queryLocalizedProjects(&where, from, "")
+	if err == nil && len(projects) == 0 && app.redirectSlug(writer, req, SlugProject, slug, projectLiveSQL) {
 		return
 	}
-	app.RenderJson(writer, http.StatusOK, u)
+	if err != nil || len(projects) == 0 {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Project [%s] not found", slug))
+		return
+	}
+	visibleOnly := !app.authenticate(req).IsEditor()
+	if projects[0].RelatedNews, err = app.relatedNews(projects[0].ID, locale, visibleOnly); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get related news")
+		return
+	}
+	setContentLanguage(writer, locale)
+	app.renderVersioned(writer, req, versionETag(projects[0].Version, locale), projects[0])
 }
 
-// DeleteProjectItem deletes project item from database and creates a json response of the data
-func (app *App) DeleteProjectItem(writer http.ResponseWriter, req *http.Request) {
+// UpdateProjectItem updates a project item. A changed name gets a new slug,
+// the old one keeps working as a redirect.
+func (app *App) UpdateProjectItem(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
 	params := mux.Vars(req)
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
+		return
+	}
+
+	var request ProjectRequest
+	err = unmarshalRequest(req, reqBody, &request)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed convert JSON")
+		return
+	}
 
-	sql := fmt.Sprint("DELETE FROM project WHERE uid=$1")
-	rows, err := app.db.Query(sql, params["id"])
+	status, message, errorValidate := request.ValidateProject()
+	if errorValidate != nil {
+		app.RenderErrorResponse(writer, status, errorValidate, message)
+		return
+	}
+
+	tx, err := app.db.Begin()
 	if err != nil {
-		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete project")
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update project")
+		return
+	}
+	defer tx.Rollback()
+
+	response, ex := app.updateProject(tx, req, params["id"], req.Header.Get("If-Match"), request)
+	if ex != nil {
+		app.renderVersionedError(writer, *ex)
 		return
 	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update project")
+		return
+	}
+	writer.Header().Set("ETag", versionETag(response.Version, ""))
+	app.RenderJson(writer, http.StatusOK, response)
+}
+
+// updateProject updates a project item whose version matches ifMatch,
+// recording a revision and an audit entry
+func (app *App) updateProject(tx dbExecutor, req *http.Request, id string, ifMatch string, request ProjectRequest) (ProjectResponse, *ErrorResponse) {
+	current, err := scanProject(tx.QueryRow("SELECT "+projectColumns+" FROM project WHERE uid=$1 AND deleted_at IS NULL FOR UPDATE", id))
+	if err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusNotFound, Error: err, Message: fmt.Sprintf("Project [%s] not found", id)}
+	}
+	if ex := matchVersion(ifMatch, current.Version, true); ex != nil {
+		return ProjectResponse{}, ex
+	}
+	before := []ProjectResponse{current}
+	if err = attachProjectMetadata(tx, before); err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to update project"}
+	}
 
-	rows.Next()
-	app.RenderJson(writer, http.StatusOK, nil)
+	sql := "UPDATE project SET slug=$1, project_name=$2, detail=$3, project_images=$4, start_date=$5, finish_date=$6, version=version+1 " +
+		"WHERE uid=$7 RETURNING " + projectColumns
+	var u ProjectResponse
+	_, err = writeWithSlug(tx, func() (string, error) {
+		return renameSlug(tx, SlugProject, current.ID, current.Slug, request.ProjectName)
+	}, func(slug string) (err error) {
+		u, err = scanProject(tx.QueryRow(sql, slug, request.ProjectName, request.Detail, request.ProjectImages,
+			nullDate(request.StartDate), nullDate(request.FinishDate), current.ID))
+		return err
+	})
+	if err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to update project"}
+	}
+	if err = saveProjectMetadata(tx, current.ID, request); err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to save project metadata"}
+	}
+	response := []ProjectResponse{u}
+	if err = attachProjectMetadata(tx, response); err == nil {
+		err = app.saveRequestRevision(tx, req, projectRevisions, current.ID)
+	}
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditUpdate, resourceType: AuditProject, resourceID: current.ID,
+			before: before[0], after: response[0]})
+	}
+	if err != nil {
+		return ProjectResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to update project"}
+	}
+	return response[0], nil
+}
+
+// DeleteProjectItem moves a project item to the trash and creates a json response of the data
+func (app *App) DeleteProjectItem(writer http.ResponseWriter, req *http.Request) {
+	if app.softDelete(writer, req, trashProjects) {
+		app.RenderJson(writer, http.StatusOK, nil)
+	}
 }
 
 // ValidateProject validates request
@@ -137,5 +499,9 @@ func (request *ProjectRequest) ValidateProject() (int, string, error) {
 		return http.StatusBadRequest, "Detail not null", fmt.Errorf("Detail is wrong")
 	}
 
+	if err := request.validateProjectMetadata(); err != nil {
+		return http.StatusBadRequest, "Project metadata is wrong", err
+	}
+
 	return http.StatusOK, "", nil
 }
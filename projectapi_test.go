@@ -0,0 +1,79 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProjectStatus(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := time.Parse(dateLayout, value)
+		return parsed
+	}
+	// 00:30 on 2026-03-10 in Istanbul is still 2026-03-09 in UTC
+	lateNight := time.Date(2026, 3, 10, 0, 30, 0, 0, time.FixedZone("+03", 3*60*60))
+	tests := []struct {
+		name   string
+		start  time.Time
+		finish time.Time
+		today  time.Time
+		want   string
+	}{
+		{"no dates", time.Time{}, time.Time{}, lateNight, ProjectPlanned},
+		{"starts today", date("2026-03-10"), time.Time{}, lateNight, ProjectOngoing},
+		{"starts tomorrow", date("2026-03-11"), time.Time{}, lateNight, ProjectPlanned},
+		{"finishes today", date("2026-01-01"), date("2026-03-10"), lateNight, ProjectOngoing},
+		{"finished yesterday", date("2026-01-01"), date("2026-03-09"), lateNight, ProjectCompleted},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ProjectStatus(test.start, test.finish, test.today); got != test.want {
+				t.Errorf("ProjectStatus = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNullDate(t *testing.T) {
+	if got := nullDate(time.Time{}); got != nil {
+		t.Errorf("nullDate(zero) = %v, want nil", got)
+	}
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	if got := nullDate(date); got != date {
+		t.Errorf("nullDate(%v) = %v", date, got)
+	}
+}
+
+// TestProjectStatusSQL checks that projectStatusSQL agrees with ProjectStatus
+// for dates written with nullDate. It needs a database, given as a lib/pq
+// connection string in CERCI_TEST_DATABASE.
+func TestProjectStatusSQL(t *testing.T) {
+	dsn := os.Getenv("CERCI_TEST_DATABASE")
+	if dsn == "" {
+		t.Skip("CERCI_TEST_DATABASE is not set")
+	}
+	db, err := dbsql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	today := projectToday()
+	days := func(n int) time.Time { return today.AddDate(0, 0, n) }
+	dates := []time.Time{{}, days(-30), days(-1), today, days(1), days(30)}
+	for _, start := range dates {
+		for _, finish := range dates {
+			var where whereBuilder
+			from := "(VALUES (" + where.Arg(nullDate(start)) + "::date," + where.Arg(nullDate(finish)) + "::date)) p(start_date,finish_date)"
+			var got string
+			if err = db.QueryRow("SELECT "+projectStatusExpr(&where)+" FROM "+from, where.Args()...).Scan(&got); err != nil {
+				t.Fatal(err)
+			}
+			if want := ProjectStatus(start, finish, today); got != want {
+				t.Errorf("start %v finish %v: sql %s, ProjectStatus %s", start, finish, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientRequest request struct
+//
+//swagger:model ClientRequest
+type ClientRequest struct {
+	Name    string `json:"name"`
+	Website string `json:"website,omitempty"`
+}
+
+// ClientResponse response struct
+//
+//swagger:response ClientResponse
+type ClientResponse struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Website string `json:"website,omitempty"`
+}
+
+// LocationRequest request struct
+//
+//swagger:model LocationRequest
+type LocationRequest struct {
+	Name      string   `json:"name,omitempty"`
+	City      string   `json:"city,omitempty"`
+	Country   string   `json:"country,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// LocationResponse response struct
+//
+//swagger:response LocationResponse
+type LocationResponse struct {
+	Name      string   `json:"name,omitempty"`
+	City      string   `json:"city,omitempty"`
+	Country   string   `json:"country,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// TeamMember request and response struct
+//
+//swagger:model TeamMember
+type TeamMember struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// defaultNearRadius is the radius of the near filter in kilometers when none is given
+const defaultNearRadius = 50.0
+
+// distanceSQL computes the great-circle distance in kilometers between the
+// location (l) and the point (?, ?), taking the latitude twice
+// The argument of asin is clamped, rounding can push it just above 1.
+const distanceSQL = "2*6371*asin(LEAST(1,sqrt(power(sin(radians(l.latitude-?)/2),2)+" +
+	"cos(radians(?))*cos(radians(l.latitude))*power(sin(radians(l.longitude-?)/2),2))))"
+
+// isEmpty reports whether the location carries no information, which removes it
+func (l *LocationRequest) isEmpty() bool {
+	return l.Name == "" && l.City == "" && l.Country == "" && l.Latitude == nil && l.Longitude == nil
+}
+
+// validateProjectMetadata validates the structured fields of a project request
+func (request *ProjectRequest) validateProjectMetadata() error {
+	if location := request.Location; location != nil {
+		if (location.Latitude == nil) != (location.Longitude == nil) {
+			return fmt.Errorf("latitude and longitude must be given together")
+		}
+		if location.Latitude != nil && (*location.Latitude < -90 || *location.Latitude > 90) {
+			return fmt.Errorf("latitude must be between -90 and 90")
+		}
+		if location.Longitude != nil && (*location.Longitude < -180 || *location.Longitude > 180) {
+			return fmt.Errorf("longitude must be between -180 and 180")
+		}
+	}
+	for _, member := range request.Team {
+		if strings.TrimSpace(member.Name) == "" || strings.TrimSpace(member.Role) == "" {
+			return fmt.Errorf("team members need a name and a role")
+		}
+	}
+	return nil
+}
+
+// saveProjectMetadata stores the client, location, technologies and team sent
+// with a project request. Nil fields leave the current values untouched, an
+// empty client or location removes it.
+func saveProjectMetadata(db dbExecutor, projectID int, request ProjectRequest) error {
+	if request.Client != nil {
+		var clientID dbsql.NullInt64
+		if name := strings.TrimSpace(request.Client.Name); name != "" {
+			sql := "INSERT INTO client(name,slug,website,created) VALUES($1,$2,NULLIF($3,''),$4) " +
+				"ON CONFLICT (slug) DO UPDATE SET website=COALESCE(EXCLUDED.website,client.website) RETURNING id"
+			if err := db.QueryRow(sql, name, Slugify(name), request.Client.Website, time.Now()).Scan(&clientID); err != nil {
+				return err
+			}
+		}
+		if _, err := db.Exec("UPDATE project SET client_id=$1 WHERE uid=$2", clientID, projectID); err != nil {
+			return err
+		}
+	}
+
+	if location := request.Location; location != nil {
+		if location.isEmpty() {
+			if _, err := db.Exec("DELETE FROM project_location WHERE project_uid=$1", projectID); err != nil {
+				return err
+			}
+		} else {
+			sql := "INSERT INTO project_location(project_uid,name,city,country,latitude,longitude) " +
+				"VALUES($1,NULLIF($2,''),NULLIF($3,''),NULLIF($4,''),$5,$6) ON CONFLICT (project_uid) DO UPDATE SET " +
+				"name=EXCLUDED.name, city=EXCLUDED.city, country=EXCLUDED.country, latitude=EXCLUDED.latitude, longitude=EXCLUDED.longitude"
+			_, err := db.Exec(sql, projectID, location.Name, location.City, location.Country, location.Latitude, location.Longitude)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if request.Technologies != nil {
+		if err := technologies.setTerms(db, projectID, request.Technologies); err != nil {
+			return err
+		}
+	}
+
+	if request.Team != nil {
+		if _, err := db.Exec("DELETE FROM project_member WHERE project_uid=$1", projectID); err != nil {
+			return err
+		}
+		for i, member := range request.Team {
+			sql := "INSERT INTO project_member(project_uid,name,role,position) VALUES($1,$2,$3,$4)"
+			if _, err := db.Exec(sql, projectID, strings.TrimSpace(member.Name), strings.TrimSpace(member.Role), i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// attachProjectMetadata fills the client, location, technologies and team of project items
+func attachProjectMetadata(db dbExecutor, projects []ProjectResponse) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	ids := make([]int, len(projects))
+	index := map[int]*ProjectResponse{}
+	for i := range projects {
+		ids[i] = projects[i].ID
+		index[projects[i].ID] = &projects[i]
+	}
+
+	sql := "SELECT p.uid,c.id,c.name,c.slug,COALESCE(c.website,'') FROM project p JOIN client c ON c.id=p.client_id WHERE p.uid = ANY($1)"
+	rows, err := db.Query(sql, intArray(ids))
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var projectID int
+		client := ClientResponse{}
+		if err = rows.Scan(&projectID, &client.ID, &client.Name, &client.Slug, &client.Website); err != nil {
+			rows.Close()
+			return err
+		}
+		index[projectID].Client = &client
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	sql = "SELECT project_uid,COALESCE(name,''),COALESCE(city,''),COALESCE(country,''),latitude,longitude " +
+		"FROM project_location WHERE project_uid = ANY($1)"
+	rows, err = db.Query(sql, intArray(ids))
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var projectID int
+		var latitude, longitude dbsql.NullFloat64
+		location := LocationResponse{}
+		if err = rows.Scan(&projectID, &location.Name, &location.City, &location.Country, &latitude, &longitude); err != nil {
+			rows.Close()
+			return err
+		}
+		if latitude.Valid && longitude.Valid {
+			location.Latitude, location.Longitude = &latitude.Float64, &longitude.Float64
+		}
+		index[projectID].Location = &location
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	sql = "SELECT project_uid,name,role FROM project_member WHERE project_uid = ANY($1) ORDER BY project_uid,position"
+	rows, err = db.Query(sql, intArray(ids))
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var projectID int
+		member := TeamMember{}
+		if err = rows.Scan(&projectID, &member.Name, &member.Role); err != nil {
+			rows.Close()
+			return err
+		}
+		index[projectID].Team = append(index[projectID].Team, member)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	projectTechnologies, err := technologies.terms(db, ids)
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		projects[i].Technologies = projectTechnologies[projects[i].ID]
+	}
+	return nil
+}
+
+// addProjectMetadataFilters adds the client, technology, member, role, city,
+// country and near filters to where
+func addProjectMetadataFilters(where *whereBuilder, query url.Values) error {
+	if client := query.Get("client"); client != "" {
+		where.Add("EXISTS(SELECT 1 FROM client c WHERE c.id=p.client_id AND c.slug=?)", Slugify(client))
+	}
+	for _, technology := range query["technology"] {
+		where.Add(technologies.hasTermSQL(), Slugify(technology))
+	}
+
+	var memberConditions []string
+	var memberArgs []interface{}
+	if member := query.Get("member"); member != "" {
+		memberConditions = append(memberConditions, "m.name ILIKE ?")
+		memberArgs = append(memberArgs, "%"+escapeLike(member)+"%")
+	}
+	if role := query.Get("role"); role != "" {
+		memberConditions = append(memberConditions, "lower(m.role)=lower(?)")
+		memberArgs = append(memberArgs, role)
+	}
+	if len(memberConditions) > 0 {
+		where.Add("EXISTS(SELECT 1 FROM project_member m WHERE m.project_uid=p.uid AND "+
+			strings.Join(memberConditions, " AND ")+")", memberArgs...)
+	}
+
+	if city := query.Get("city"); city != "" {
+		where.Add("EXISTS(SELECT 1 FROM project_location l WHERE l.project_uid=p.uid AND lower(l.city)=lower(?))", city)
+	}
+	if country := query.Get("country"); country != "" {
+		where.Add("EXISTS(SELECT 1 FROM project_location l WHERE l.project_uid=p.uid AND lower(l.country)=lower(?))", country)
+	}
+
+	if near := query.Get("near"); near != "" {
+		coordinates := strings.Split(near, ",")
+		if len(coordinates) != 2 {
+			return fmt.Errorf("near must be formatted as latitude,longitude")
+		}
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64)
+		if err != nil || math.IsNaN(latitude) || latitude < -90 || latitude > 90 {
+			return fmt.Errorf("near latitude must be between -90 and 90")
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64)
+		if err != nil || math.IsNaN(longitude) || longitude < -180 || longitude > 180 {
+			return fmt.Errorf("near longitude must be between -180 and 180")
+		}
+		radius := defaultNearRadius
+		if value := query.Get("radius"); value != "" {
+			radius, err = strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(radius) || math.IsInf(radius, 0) || radius <= 0 {
+				return fmt.Errorf("radius must be a positive number of kilometers")
+			}
+		}
+		where.Add("EXISTS(SELECT 1 FROM project_location l WHERE l.project_uid=p.uid AND l.latitude IS NOT NULL AND "+
+			distanceSQL+" <= ?)", latitude, latitude, longitude, radius)
+	}
+	return nil
+}
+
+// GetClients lists all clients
+func (app *App) GetClients(writer http.ResponseWriter, req *http.Request) {
+	rows, err := app.db.Query("SELECT id,name,slug,COALESCE(website,'') FROM client ORDER BY name")
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get client list")
+		return
+	}
+	defer rows.Close()
+
+	clients := []ClientResponse{}
+	for rows.Next() {
+		client := ClientResponse{}
+		if err = rows.Scan(&client.ID, &client.Name, &client.Slug, &client.Website); err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get client list")
+			return
+		}
+		clients = append(clients, client)
+	}
+	app.RenderJson(writer, http.StatusOK, clients)
+}
+
+// GetTechnologies lists all technologies
+func (app *App) GetTechnologies(writer http.ResponseWriter, req *http.Request) {
+	app.listTerms(writer, technologies)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// whereBuilder collects sql conditions together with their positional arguments
+type whereBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// Add adds a condition. Every "?" in the condition is replaced with the next
+// positional parameter, taken from args in order.
+func (w *whereBuilder) Add(condition string, args ...interface{}) {
+	var builder strings.Builder
+	next := 0
+	for _, char := range condition {
+		if char == '?' && next < len(args) {
+			builder.WriteString(w.Arg(args[next]))
+			next++
+			continue
+		}
+		builder.WriteRune(char)
+	}
+	w.conditions = append(w.conditions, builder.String())
+}
+
+// Arg registers an argument and returns its positional parameter
+func (w *whereBuilder) Arg(value interface{}) string {
+	w.args = append(w.args, value)
+	return "$" + strconv.Itoa(len(w.args))
+}
+
+// Args returns the positional arguments
+func (w *whereBuilder) Args() []interface{} {
+	return w.args
+}
+
+// SQL returns the WHERE clause, or an empty string without conditions
+func (w *whereBuilder) SQL() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes value for use in a LIKE or ILIKE pattern, so that it
+// only matches itself
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// intArray converts ids into a postgres array parameter, for use with = ANY($n)
+func intArray(ids []int) interface{} {
+	values := make([]int64, len(ids))
+	for i, id := range ids {
+		values[i] = int64(id)
+	}
+	return pq.Array(values)
+}
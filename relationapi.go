@@ -1,12 +1,14 @@
 package main
 
 import (
-	"database/sql"
+	dbsql "database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -40,26 +42,24 @@ type RelationResponse struct {
 	Name     string `json:"name"`
 	TypeID   int64  `json:"type_id"`
 	ParentID int    `json:"parent_id"`
+	// PathID is the id to use as parent_id of relations below this one
+	PathID int `json:"path_id"`
 }
 
 // CreateRelationType creating new relation type
-func (app *App) CreateRelationType(relationType string) (int64, *ErrorResponse) {
+func (app *App) CreateRelationType(db dbExecutor, relationType string) (int64, *ErrorResponse) {
 	var typeID int64
 	sql := fmt.Sprint("SELECT id FROM relation_type WHERE name=$1")
-	rows, err := app.db.Query(sql, relationType)
-	if err != nil {
-		return 0, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to fetch data from DB"}
-	}
-	if rows.Next() {
-		err = rows.Scan(&typeID)
-		if err != nil {
-			return 0, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to save data"}
-		}
+	err := db.QueryRow(sql, relationType).Scan(&typeID)
+	if err == nil {
 		return typeID, nil
 	}
+	if err != dbsql.ErrNoRows {
+		return 0, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to fetch data from DB"}
+	}
 
 	sql = fmt.Sprintf("INSERT INTO relation_type(name,created) VALUES($1,$2) returning id;")
-	err = app.db.QueryRow(sql, relationType, time.Now()).Scan(&typeID)
+	err = db.QueryRow(sql, relationType, time.Now()).Scan(&typeID)
 	if err != nil {
 		return 0, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to save data"}
 	}
@@ -76,13 +76,13 @@ func (app *App) AddRelation(writer http.ResponseWriter, req *http.Request) {
 	}
 
 	var request RelationRequest
-	err = json.Unmarshal(reqBody, &request)
+	err = unmarshalRequest(req, reqBody, &request)
 	if err != nil {
 		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to parse request body")
 		return
 	}
 
-	typeID, errRelation := app.CreateRelationType(request.Type)
+	typeID, errRelation := app.CreateRelationType(app.db, request.Type)
 
 	if errRelation != nil {
 		app.RenderError(writer, *errRelation)
@@ -93,7 +93,7 @@ func (app *App) AddRelation(writer http.ResponseWriter, req *http.Request) {
 
 	if request.ParentID != 0 {
 		sqlRelation := fmt.Sprintf("SELECT id,name,type_id,path_id FROM relation WHERE id = $1;")
-		var rows *sql.Rows
+		var rows *dbsql.Rows
 		rows, err = app.db.Query(sqlRelation, request.ParentID)
 		if err != nil {
 			app.RenderErrorResponse(writer, http.StatusNotFound, err, "Sequence not found"+strconv.Itoa(request.ParentID))
@@ -111,7 +111,7 @@ func (app *App) AddRelation(writer http.ResponseWriter, req *http.Request) {
 	var path string
 	var nextSequence int
 	if relationSQL != nil {
-		nextSequence, err = app.NextForSequence(writer, "relation_path_id_seq")
+		nextSequence, err = app.NextForSequence(app.db, "relation_path_id_seq")
 
 		if err != nil {
 			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, ""+strconv.Itoa(request.ParentID))
@@ -132,15 +132,17 @@ func (app *App) AddRelation(writer http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	app.auditLogged(req, auditEntry{action: AuditCreate, resourceType: AuditRelation, resourceID: string(lastInsertID),
+		after: map[string]interface{}{"name": request.Name, "type_id": typeID, "path": path, "parent_id": request.ParentID}})
 	app.RenderJson(writer, http.StatusOK, RelationResponse{Name: request.Name, TypeID: typeID, ID: lastInsertID})
 }
 
 // GetRelationSQL fetches relation path if parent not exist adds its relation path.
-func (app *App) GetRelationSQL(writer http.ResponseWriter, name string, typeID int64, parent Relation, level int) (*sql.Rows, ErrorResponse) {
+func (app *App) GetRelationSQL(writer http.ResponseWriter, name string, typeID int64, parent Relation, level int) (*dbsql.Rows, ErrorResponse) {
 
 	sqlVar := fmt.Sprintf("SELECT * FROM relation WHERE name=$1 AND type_id=$2")
 
-	var rows *sql.Rows
+	var rows *dbsql.Rows
 	var err error
 	if parent.ID != 0 {
 		sqlVar += fmt.Sprintf(" AND path operator(public.<@) $3")
@@ -158,11 +160,131 @@ func (app *App) GetRelationSQL(writer http.ResponseWriter, name string, typeID i
 	return rows, ErrorResponse{}
 }
 
+// createRelation inserts a relation below the relation whose path_id is
+// request.ParentID and records it in the audit log. It is used by the bulk
+// endpoint and, unlike AddRelation, refuses a name that already exists below
+// the parent.
+func (app *App) createRelation(db dbExecutor, req *http.Request, request RelationRequest) (RelationResponse, *ErrorResponse) {
+	typeID, errRelation := app.CreateRelationType(db, request.Type)
+	if errRelation != nil {
+		return RelationResponse{}, errRelation
+	}
+
+	var parent Relation
+	if request.ParentID != 0 {
+		sqlRelation := fmt.Sprintf("SELECT path_id,name,type_id,COALESCE(path::text,'') FROM relation WHERE path_id = $1;")
+		err := db.QueryRow(sqlRelation, request.ParentID).Scan(&parent.ID, &parent.Name, &parent.TypeID, &parent.Path)
+		if err != nil {
+			return RelationResponse{}, &ErrorResponse{Status: http.StatusNotFound, Error: err, Message: "Sequence not found" + strconv.Itoa(request.ParentID)}
+		}
+	}
+
+	exists, err := app.RelationExists(db, request.Name, typeID, parent)
+	if err != nil {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed save path"}
+	}
+	if exists {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusConflict, Error: fmt.Errorf("relation %q already exists", request.Name),
+			Message: "Relation already exists"}
+	}
+
+	nextSequence, err := app.NextForSequence(db, "relation_path_id_seq")
+	if err != nil {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "" + strconv.Itoa(request.ParentID)}
+	}
+	path := strconv.Itoa(nextSequence)
+	if request.ParentID != 0 {
+		path = parent.Path + "." + path
+	}
+
+	sql := fmt.Sprintf("INSERT INTO relation(name,type_id,path,path_id,created) VALUES($1,$2,$3,$4,$5) returning id;")
+	var lastInsertID []uint8 //UUID
+	err = db.QueryRow(sql, request.Name, typeID, path, nextSequence, time.Now()).Scan(&lastInsertID)
+	if err != nil {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to save data"}
+	}
+
+	err = app.recordAudit(db, req, auditEntry{action: AuditCreate, resourceType: AuditRelation, resourceID: string(lastInsertID),
+		after: map[string]interface{}{"name": request.Name, "type_id": typeID, "path": path, "parent_id": request.ParentID}})
+	if err != nil {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to save data"}
+	}
+	return RelationResponse{Name: request.Name, TypeID: typeID, ID: lastInsertID, ParentID: request.ParentID, PathID: nextSequence}, nil
+}
+
+// RelationExists reports whether a relation with name and type exists below parent,
+// or anywhere when parent is empty
+func (app *App) RelationExists(db dbExecutor, name string, typeID int64, parent Relation) (bool, error) {
+	sqlVar := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM relation WHERE name=$1 AND type_id=$2")
+	args := []interface{}{name, typeID}
+	if parent.ID != 0 {
+		sqlVar += fmt.Sprintf(" AND path operator(public.<@) $3::ltree")
+		args = append(args, parent.Path)
+	}
+	var exists bool
+	err := db.QueryRow(sqlVar+")", args...).Scan(&exists)
+	return exists, err
+}
+
+// updateRelation renames a relation
+func (app *App) updateRelation(db dbExecutor, req *http.Request, id string, request RelationRequest) (RelationResponse, *ErrorResponse) {
+	if strings.TrimSpace(request.Name) == "" {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusBadRequest, Error: errors.New("name is required"), Message: "Failed to save data"}
+	}
+	before, err := relationSnapshot(db, id)
+	if err == dbsql.ErrNoRows {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusNotFound, Error: NotFoundError, Message: fmt.Sprintf("Relation [%s] not found", id)}
+	}
+	if err != nil {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to save data"}
+	}
+
+	response := RelationResponse{Name: request.Name}
+	sql := "UPDATE relation SET name=$1 WHERE id=$2 RETURNING id,type_id,path_id"
+	if err = db.QueryRow(sql, request.Name, id).Scan(&response.ID, &response.TypeID, &response.PathID); err != nil {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusBadRequest, Error: err, Message: "Failed to save data"}
+	}
+	after, err := relationSnapshot(db, id)
+	if err == nil {
+		err = app.recordAudit(db, req, auditEntry{action: AuditUpdate, resourceType: AuditRelation, resourceID: id, before: before, after: after})
+	}
+	if err != nil {
+		return RelationResponse{}, &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to save data"}
+	}
+	return response, nil
+}
+
+// deleteRelation deletes a relation together with the relations below it
+func (app *App) deleteRelation(db dbExecutor, req *http.Request, id string) *ErrorResponse {
+	before, err := relationSnapshot(db, id)
+	if err == dbsql.ErrNoRows {
+		return &ErrorResponse{Status: http.StatusNotFound, Error: NotFoundError, Message: fmt.Sprintf("Relation [%s] not found", id)}
+	}
+	if err == nil {
+		sql := "DELETE FROM relation WHERE path operator(public.<@) (SELECT path FROM relation WHERE id=$1) OR id=$1"
+		_, err = db.Exec(sql, id)
+	}
+	if err == nil {
+		err = app.recordAudit(db, req, auditEntry{action: AuditDelete, resourceType: AuditRelation, resourceID: id, before: before})
+	}
+	if err != nil {
+		return &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed to delete relation"}
+	}
+	return nil
+}
+
+// relationSnapshot returns a relation as JSON for the audit log, locking it
+func relationSnapshot(db dbExecutor, id string) (json.RawMessage, error) {
+	var snapshot []byte
+	err := db.QueryRow("SELECT to_jsonb(x) FROM relation x WHERE id=$1 FOR UPDATE", id).Scan(&snapshot)
+	return json.RawMessage(snapshot), err
+}
+
 //NextForSequence finds next sequence
-func (app *App) NextForSequence(writer http.ResponseWriter, sequenceName string) (int, error) {
+func (app *App) NextForSequence(db dbExecutor, sequenceName string) (int, error) {
 	sql := fmt.Sprintf("SELECT NEXTVAL($1) as id")
 
-	row := app.db.QueryRow(sql, sequenceName)
+	row := db.QueryRow(sql, sequenceName)
 
 	var nextValue int
 
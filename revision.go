@@ -0,0 +1,425 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RevisionResponse response struct
+//
+//swagger:response RevisionResponse
+type RevisionResponse struct {
+	Revision  int             `json:"revision"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	Created   time.Time       `json:"created"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// RevisionDiffResponse response struct
+//
+//swagger:response RevisionDiffResponse
+type RevisionDiffResponse struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is a field that differs between two revisions
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// versioned describes a resource whose rows are kept as revisions. Every change
+// to the item row writes a revision: create, update, publish, unpublish,
+// archive, expiry, revert, delete and restore. Tags, categories, translations
+// and news-project links live in other tables, so changing them only bumps the
+// version through bumpVersion; a revision would repeat the previous one.
+type versioned struct {
+	resource    string
+	table       string
+	titleColumn string
+	// columns restored by a revert, the title is restored through the slug
+	revertColumns []string
+	// extra assignments made by a revert
+	touchSQL string
+}
+
+var (
+	newsRevisions = versioned{"news", "news_item", "news_title",
+		[]string{"news_title", "detail", "detail_format", "detail_html", "news_image", "publish_at", "expire_at"}, ", updated=now(), version=version+1"}
+	projectRevisions = versioned{"project", "project", "project_name",
+		[]string{"project_name", "detail", "project_images", "start_date", "finish_date", "client_id"}, ", version=version+1"}
+)
+
+// saveRevision stores the current row of an item as its next revision. Call it
+// in the transaction that changed the row, after the change. The row is locked
+// before the next revision number is read, so writers of the same item number
+// their revisions one after another whatever locks they took before.
+func saveRevision(db dbExecutor, v versioned, id interface{}, actor string, requestID string) error {
+	if _, err := db.Exec("SELECT 1 FROM "+v.table+" WHERE uid=$1 FOR UPDATE", id); err != nil {
+		return err
+	}
+	sql := "INSERT INTO content_revision(resource_type,resource_uid,revision,data,actor,request_id,created) " +
+		"SELECT $1, x.uid, COALESCE((SELECT MAX(revision) FROM content_revision WHERE resource_type=$1 AND resource_uid=x.uid),0)+1, " +
+		"to_jsonb(x), $2, NULLIF($3,''), now() FROM " + v.table + " x WHERE x.uid=$4"
+	_, err := db.Exec(sql, v.resource, actor, requestID, id)
+	return err
+}
+
+// saveRequestRevision stores a revision made by the caller of req
+func (app *App) saveRequestRevision(db dbExecutor, req *http.Request, v versioned, id interface{}) error {
+	return saveRevision(db, v, id, actorName(app.authenticate(req)), requestID(req))
+}
+
+// stripOmittedFields replaces the auditOmittedFields of a json object with
+// their size, binary data is not worth showing
+func stripOmittedFields(data []byte) json.RawMessage {
+	var fields map[string]interface{}
+	if json.Unmarshal(data, &fields) != nil {
+		return json.RawMessage(data)
+	}
+	summarizeBinaryFields(fields)
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		return json.RawMessage(data)
+	}
+	return stripped
+}
+
+// summarizeBinaryFields replaces binary values with a description of their size
+func summarizeBinaryFields(fields map[string]interface{}) {
+	for _, name := range auditOmittedFields {
+		value, ok := fields[name]
+		if !ok || value == nil {
+			continue
+		}
+		// bytea columns are hex encoded by to_jsonb, "\x" followed by two digits per byte
+		if hex, ok := value.(string); ok {
+			fields[name] = fmt.Sprintf("%d bytes", len(strings.TrimPrefix(hex, `\x`))/2)
+		}
+	}
+}
+
+// GetNewsRevisions lists the revisions of a news item
+func (app *App) GetNewsRevisions(writer http.ResponseWriter, req *http.Request) {
+	app.listRevisions(writer, req, newsRevisions)
+}
+
+// GetNewsRevision returns a single revision of a news item
+func (app *App) GetNewsRevision(writer http.ResponseWriter, req *http.Request) {
+	app.getRevision(writer, req, newsRevisions)
+}
+
+// GetNewsRevisionDiff compares two revisions of a news item
+func (app *App) GetNewsRevisionDiff(writer http.ResponseWriter, req *http.Request) {
+	app.diffRevisions(writer, req, newsRevisions)
+}
+
+// RevertNewsItem restores a news item to one of its revisions
+func (app *App) RevertNewsItem(writer http.ResponseWriter, req *http.Request) {
+	app.revert(writer, req, newsRevisions, SlugNews)
+}
+
+// GetProjectRevisions lists the revisions of a project item
+func (app *App) GetProjectRevisions(writer http.ResponseWriter, req *http.Request) {
+	app.listRevisions(writer, req, projectRevisions)
+}
+
+// GetProjectRevision returns a single revision of a project item
+func (app *App) GetProjectRevision(writer http.ResponseWriter, req *http.Request) {
+	app.getRevision(writer, req, projectRevisions)
+}
+
+// GetProjectRevisionDiff compares two revisions of a project item
+func (app *App) GetProjectRevisionDiff(writer http.ResponseWriter, req *http.Request) {
+	app.diffRevisions(writer, req, projectRevisions)
+}
+
+// RevertProjectItem restores a project item to one of its revisions
+func (app *App) RevertProjectItem(writer http.ResponseWriter, req *http.Request) {
+	app.revert(writer, req, projectRevisions, SlugProject)
+}
+
+func (app *App) listRevisions(writer http.ResponseWriter, req *http.Request, v versioned) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	sql := "SELECT revision,actor,COALESCE(request_id,''),created FROM content_revision " +
+		"WHERE resource_type=$1 AND resource_uid=$2 ORDER BY revision DESC"
+	rows, err := app.db.Query(sql, v.resource, mux.Vars(req)["id"])
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get revisions")
+		return
+	}
+	defer rows.Close()
+
+	revisions := []RevisionResponse{}
+	for rows.Next() {
+		revision := RevisionResponse{}
+		if err = rows.Scan(&revision.Revision, &revision.Actor, &revision.RequestID, &revision.Created); err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get revisions")
+			return
+		}
+		revisions = append(revisions, revision)
+	}
+	if len(revisions) == 0 {
+		app.RenderErrorResponse(writer, http.StatusNotFound, NotFoundError, fmt.Sprintf("Item [%s] not found", mux.Vars(req)["id"]))
+		return
+	}
+	app.RenderJson(writer, http.StatusOK, revisions)
+}
+
+// loadRevision reads a revision with its full data
+func loadRevision(db dbExecutor, v versioned, id string, revision string) (RevisionResponse, error) {
+	response := RevisionResponse{}
+	var data []byte
+	sql := "SELECT revision,actor,COALESCE(request_id,''),created,data FROM content_revision " +
+		"WHERE resource_type=$1 AND resource_uid=$2 AND revision=$3"
+	err := db.QueryRow(sql, v.resource, id, revision).Scan(&response.Revision, &response.Actor, &response.RequestID, &response.Created, &data)
+	response.Data = data
+	return response, err
+}
+
+func (app *App) getRevision(writer http.ResponseWriter, req *http.Request, v versioned) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	params := mux.Vars(req)
+	revision, err := loadRevision(app.db, v, params["id"], params["rev"])
+	if err == dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Revision [%s] not found", params["rev"]))
+		return
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get revision")
+		return
+	}
+	revision.Data = stripOmittedFields(revision.Data)
+	app.RenderJson(writer, http.StatusOK, revision)
+}
+
+// diffRevisions compares the revisions ?from= and ?to=. to defaults to the
+// latest revision, from to the one before to.
+func (app *App) diffRevisions(writer http.ResponseWriter, req *http.Request, v versioned) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	id := mux.Vars(req)["id"]
+	query := req.URL.Query()
+
+	to := query.Get("to")
+	if to == "" {
+		var latest int
+		sql := "SELECT COALESCE(MAX(revision),0) FROM content_revision WHERE resource_type=$1 AND resource_uid=$2"
+		if err := app.db.QueryRow(sql, v.resource, id).Scan(&latest); err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get revision diff")
+			return
+		}
+		to = strconv.Itoa(latest)
+	}
+	from := query.Get("from")
+	if from == "" {
+		number, err := strconv.Atoi(to)
+		if err != nil {
+			app.RenderErrorResponse(writer, http.StatusBadRequest, err, "to must be a revision number")
+			return
+		}
+		from = strconv.Itoa(number - 1)
+	}
+
+	var revisions [2]RevisionResponse
+	for i, number := range []string{from, to} {
+		revision, err := loadRevision(app.db, v, id, number)
+		if err == dbsql.ErrNoRows {
+			app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Revision [%s] not found", number))
+			return
+		}
+		if err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get revision diff")
+			return
+		}
+		revisions[i] = revision
+	}
+
+	changes, err := diffFields(revisions[0].Data, revisions[1].Data)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get revision diff")
+		return
+	}
+	app.RenderJson(writer, http.StatusOK, RevisionDiffResponse{From: revisions[0].Revision, To: revisions[1].Revision, Changes: changes})
+}
+
+// diffFields lists the fields of two json objects that differ, sorted by name
+func diffFields(from []byte, to []byte) ([]FieldChange, error) {
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(from, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &after); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	changes := []FieldChange{}
+	for name := range names {
+		if !reflect.DeepEqual(before[name], after[name]) {
+			changes = append(changes, FieldChange{Field: name, From: before[name], To: after[name]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	// binary fields are compared in full but only reported by size
+	summarizedFrom, summarizedTo := map[string]interface{}{}, map[string]interface{}{}
+	for _, change := range changes {
+		summarizedFrom[change.Field], summarizedTo[change.Field] = change.From, change.To
+	}
+	summarizeBinaryFields(summarizedFrom)
+	summarizeBinaryFields(summarizedTo)
+	for i := range changes {
+		changes[i].From, changes[i].To = summarizedFrom[changes[i].Field], summarizedTo[changes[i].Field]
+	}
+	return changes, nil
+}
+
+// revertStatement builds the UPDATE that copies the revertColumns of revision
+// data back onto an item, taking the data as $1, the slug as $2 and the id as
+// $3. Columns missing from older revisions keep their current value. It also
+// returns the title of the revision.
+func revertStatement(v versioned, data []byte) (string, string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", "", err
+	}
+	var title string
+	if raw, ok := fields[v.titleColumn]; ok {
+		json.Unmarshal(raw, &title)
+	}
+	var columns []string
+	for _, column := range v.revertColumns {
+		if _, ok := fields[column]; ok {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return "", "", fmt.Errorf("revision has none of the columns of %s", v.table)
+	}
+	list := strings.Join(columns, ",")
+	sql := "UPDATE " + v.table + " SET (" + list + ") = (SELECT " + list + " FROM jsonb_populate_record(NULL::" + v.table + ", $1)), " +
+		"slug=$2" + v.touchSQL + " WHERE uid=$3"
+	return sql, title, nil
+}
+
+// revert copies the revertColumns of a revision back onto the item and stores
+// the result as a new revision
+func (app *App) revert(writer http.ResponseWriter, req *http.Request, v versioned, slugType string) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	params := mux.Vars(req)
+
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	defer tx.Rollback()
+
+	var currentID int
+	var currentSlug string
+	err = tx.QueryRow("SELECT uid,slug FROM "+v.table+" WHERE uid=$1 AND deleted_at IS NULL FOR UPDATE", params["id"]).Scan(&currentID, &currentSlug)
+	if err == dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Item [%s] not found", params["id"]))
+		return
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	before, err := rowSnapshot(tx, v.table, currentID)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	var current struct {
+		Version int `json:"version"`
+	}
+	if err = json.Unmarshal(before, &current); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	if !app.checkIfMatch(writer, req, current.Version, false) {
+		return
+	}
+
+	revision, err := loadRevision(tx, v, params["id"], params["rev"])
+	if err == dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("Revision [%s] not found", params["rev"]))
+		return
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+
+	sql, title, err := revertStatement(v, revision.Data)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	_, err = writeWithSlug(tx, func() (string, error) {
+		return renameSlug(tx, slugType, currentID, currentSlug, title)
+	}, func(slug string) error {
+		_, err := tx.Exec(sql, string(revision.Data), slug, currentID)
+		return err
+	})
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	if err = app.saveRequestRevision(tx, req, v, currentID); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	after, err := rowSnapshot(tx, v.table, currentID)
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditRevert, resourceType: v.resource, resourceID: currentID, before: before, after: after})
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+
+	var latest RevisionResponse
+	var data []byte
+	sql = "SELECT revision,actor,COALESCE(request_id,''),created,data FROM content_revision " +
+		"WHERE resource_type=$1 AND resource_uid=$2 ORDER BY revision DESC LIMIT 1"
+	err = tx.QueryRow(sql, v.resource, currentID).Scan(&latest.Revision, &latest.Actor, &latest.RequestID, &latest.Created, &data)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to revert")
+		return
+	}
+	latest.Data = stripOmittedFields(data)
+	app.RenderJson(writer, http.StatusOK, latest)
+}
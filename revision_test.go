@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    []FieldChange
+		wantErr bool
+	}{
+		{"identical", `{"a":1,"b":"x"}`, `{"b":"x","a":1}`, []FieldChange{}, false},
+		{"changed, added and removed sorted by field", `{"title":"Old","gone":true,"same":1}`, `{"title":"New","added":[1],"same":1}`,
+			[]FieldChange{{Field: "added", From: nil, To: []interface{}{1.0}}, {Field: "gone", From: true, To: nil},
+				{Field: "title", From: "Old", To: "New"}}, false},
+		{"null and missing are equal", `{"a":null}`, `{}`, []FieldChange{}, false},
+		{"nested values compared in full", `{"o":{"k":[1,2]}}`, `{"o":{"k":[1,3]}}`,
+			[]FieldChange{{Field: "o", From: map[string]interface{}{"k": []interface{}{1.0, 2.0}}, To: map[string]interface{}{"k": []interface{}{1.0, 3.0}}}}, false},
+		{"binary fields reported by size", `{"news_image":"\\x0102","project_images":"\\x01"}`, `{"news_image":"\\x010203","project_images":"\\x01"}`,
+			[]FieldChange{{Field: "news_image", From: "2 bytes", To: "3 bytes"}}, false},
+		{"binary field cleared", `{"news_image":"\\x0102"}`, `{"news_image":null}`,
+			[]FieldChange{{Field: "news_image", From: "2 bytes", To: nil}}, false},
+		{"invalid from", `{`, `{}`, nil, true},
+		{"invalid to", `{}`, `[]`, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := diffFields([]byte(test.from), []byte(test.to))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got  %+v\nwant %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRevertStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         versioned
+		data      string
+		wantSQL   string
+		wantTitle string
+		wantErr   bool
+	}{
+		{"news", newsRevisions,
+			`{"uid":4,"news_title":"Title","detail":"d","detail_format":"html","detail_html":"<p>d</p>","news_image":null,
+			"publish_at":null,"expire_at":null,"status":"archived","version":7,"deleted_at":null,"slug":"title"}`,
+			"UPDATE news_item SET (news_title,detail,detail_format,detail_html,news_image,publish_at,expire_at) = " +
+				"(SELECT news_title,detail,detail_format,detail_html,news_image,publish_at,expire_at FROM jsonb_populate_record(NULL::news_item, $1)), " +
+				"slug=$2, updated=now(), version=version+1 WHERE uid=$3", "Title", false},
+		{"project", projectRevisions,
+			`{"project_name":"P","detail":"d","project_images":null,"start_date":"2020-01-01","finish_date":null,"client_id":2}`,
+			"UPDATE project SET (project_name,detail,project_images,start_date,finish_date,client_id) = " +
+				"(SELECT project_name,detail,project_images,start_date,finish_date,client_id FROM jsonb_populate_record(NULL::project, $1)), " +
+				"slug=$2, version=version+1 WHERE uid=$3", "P", false},
+		{"older revision keeps missing columns", newsRevisions, `{"news_title":"Old","detail":"d"}`,
+			"UPDATE news_item SET (news_title,detail) = (SELECT news_title,detail FROM jsonb_populate_record(NULL::news_item, $1)), " +
+				"slug=$2, updated=now(), version=version+1 WHERE uid=$3", "Old", false},
+		{"null title", projectRevisions, `{"project_name":null,"detail":"d"}`,
+			"UPDATE project SET (project_name,detail) = (SELECT project_name,detail FROM jsonb_populate_record(NULL::project, $1)), " +
+				"slug=$2, version=version+1 WHERE uid=$3", "", false},
+		{"no revert columns", newsRevisions, `{"status":"draft"}`, "", "", true},
+		{"not an object", newsRevisions, `[]`, "", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sql, title, err := revertStatement(test.v, []byte(test.data))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("got %q, want an error", sql)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != test.wantSQL {
+				t.Errorf("sql\n got %s\nwant %s", sql, test.wantSQL)
+			}
+			if title != test.wantTitle {
+				t.Errorf("title %q, want %q", title, test.wantTitle)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+	nethtml "golang.org/x/net/html"
+)
+
+// Formats news details can be written in
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// allowedTags maps every allowed element to its allowed attributes
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil,
+	"sub": nil, "sup": nil, "blockquote": nil, "pre": nil, "code": {"class"},
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"a":     {"href", "title"},
+	"img":   {"src", "alt", "title", "width", "height"},
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil,
+	"th": {"colspan", "rowspan", "align"}, "td": {"colspan", "rowspan", "align"},
+	"figure": nil, "figcaption": nil,
+}
+
+// droppedTags are removed together with everything inside them
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true, "title": true,
+}
+
+// blockTags start a new line when converting to plain text
+var blockTags = map[string]bool{
+	"p": true, "br": true, "div": true, "li": true, "tr": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true,
+}
+
+var (
+	codeClassPattern = regexp.MustCompile(`^language-[a-zA-Z0-9_+-]+$`)
+	numberPattern    = regexp.MustCompile(`^[0-9]{1,4}$`)
+	alignPattern     = regexp.MustCompile(`^(left|right|center)$`)
+	paragraphPattern = regexp.MustCompile(`\n\s*\n`)
+)
+
+// RenderDetail converts a detail written in format into sanitized html
+func RenderDetail(source string, format string) (string, error) {
+	switch format {
+	case "", FormatText:
+		return textToHTML(source), nil
+	case FormatMarkdown:
+		rendered := blackfriday.Run([]byte(source), blackfriday.WithExtensions(blackfriday.CommonExtensions))
+		return SanitizeHTML(string(rendered)), nil
+	case FormatHTML:
+		return SanitizeHTML(source), nil
+	}
+	return "", fmt.Errorf("unknown detail format %q", format)
+}
+
+// textToHTML turns plain text into paragraphs, keeping line breaks
+func textToHTML(text string) string {
+	var builder strings.Builder
+	for _, paragraph := range paragraphPattern.Split(strings.ReplaceAll(text, "\r\n", "\n"), -1) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		builder.WriteString("<p>")
+		builder.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		builder.WriteString("</p>\n")
+	}
+	return builder.String()
+}
+
+// SanitizeHTML rebuilds html keeping only allow-listed elements and
+// attributes. Links and images may only point to http, https or mailto urls.
+func SanitizeHTML(input string) string {
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(input))
+	var builder strings.Builder
+	var open []string
+	dropDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedTags[token.Data] {
+				if tokenType == nethtml.StartTagToken {
+					dropDepth++
+				}
+				continue
+			}
+			attributes, ok := allowedTags[token.Data]
+			if !ok || dropDepth > 0 {
+				continue
+			}
+			builder.WriteString("<" + token.Data)
+			for _, attr := range token.Attr {
+				if value, ok := sanitizeAttribute(token.Data, attr, attributes); ok {
+					builder.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+				}
+			}
+			if token.Data == "a" {
+				builder.WriteString(` rel="nofollow noopener noreferrer"`)
+			}
+			builder.WriteString(">")
+			if tokenType == nethtml.StartTagToken && !isVoidTag(token.Data) {
+				open = append(open, token.Data)
+			}
+		case nethtml.EndTagToken:
+			if droppedTags[token.Data] {
+				if dropDepth > 0 {
+					dropDepth--
+				}
+				continue
+			}
+			// Close everything up to the matching open element, ignore stray end tags
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == token.Data {
+					for j := len(open) - 1; j >= i; j-- {
+						builder.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		case nethtml.TextToken:
+			if dropDepth == 0 {
+				builder.WriteString(html.EscapeString(token.Data))
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		builder.WriteString("</" + open[i] + ">")
+	}
+	return builder.String()
+}
+
+func isVoidTag(tag string) bool {
+	return tag == "br" || tag == "hr" || tag == "img"
+}
+
+// sanitizeAttribute returns the value of an allowed attribute
+func sanitizeAttribute(tag string, attr nethtml.Attribute, allowed []string) (string, bool) {
+	if attr.Namespace != "" {
+		return "", false
+	}
+	found := false
+	for _, name := range allowed {
+		if name == attr.Key {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", false
+	}
+
+	value := strings.TrimSpace(attr.Val)
+	switch attr.Key {
+	case "href", "src":
+		return value, isSafeURL(value, attr.Key == "href")
+	case "class":
+		return value, tag == "code" && codeClassPattern.MatchString(value)
+	case "width", "height", "colspan", "rowspan", "start":
+		return value, numberPattern.MatchString(value)
+	case "align":
+		return value, alignPattern.MatchString(value)
+	}
+	return value, true
+}
+
+// isSafeURL allows relative urls and http(s) urls, plus mailto for links
+func isSafeURL(value string, link bool) bool {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "":
+		// a colon before any path, query or fragment means url.Parse missed a
+		// scheme, e.g. one obfuscated with whitespace
+		colon := strings.Index(value, ":")
+		separator := strings.IndexAny(value, "/?#")
+		return colon < 0 || (separator >= 0 && separator < colon)
+	case "http", "https":
+		return true
+	case "mailto":
+		return link
+	}
+	return false
+}
+
+// PlainText extracts the text of an html fragment
+func PlainText(fragment string) string {
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(fragment))
+	var builder strings.Builder
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+		switch tokenType {
+		case nethtml.TextToken:
+			builder.WriteString(token.Data)
+		case nethtml.StartTagToken, nethtml.EndTagToken, nethtml.SelfClosingTagToken:
+			if blockTags[token.Data] {
+				builder.WriteString("\n")
+			}
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
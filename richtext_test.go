@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"allowed markup", `<p>Hello <strong>world</strong></p>`, `<p>Hello <strong>world</strong></p>`},
+		{"script", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"script upper case", `<SCRIPT>alert(1)</SCRIPT>ok`, `ok`},
+		{"script self closing", `<script src="https://evil.example/x.js"/>ok`, `ok`},
+		{"nested dropped", `<style><script>alert(1)</script></style>ok`, `ok`},
+		{"split script tag", `<scr<script>ipt>alert(1)</script>`, `ipt&gt;alert(1)`},
+		{"unknown tag keeps text", `<svg><g>text</g></svg>`, `text`},
+		{"onerror", `<img src="x.png" onerror="alert(1)">`, `<img src="x.png">`},
+		{"onclick upper case", `<a href="/news" ONCLICK="alert(1)">n</a>`, `<a href="/news" rel="nofollow noopener noreferrer">n</a>`},
+		{"style attribute", `<p style="background:url(javascript:alert(1))">p</p>`, `<p>p</p>`},
+		{"javascript url", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript url mixed case", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript url leading space", `<a href="  javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript url tab", "<a href=\"java\tscript:alert(1)\">x</a>", `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript url newline entity", `<a href="java&#x0A;script:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript url entities", `<a href="&#106;avascript&colon;alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"vbscript url", `<a href="vbscript:msgbox(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"data url image", `<img src="data:image/svg+xml;base64,PHN2Zz4=">`, `<img>`},
+		{"data url mixed case", `<img src=" DaTa:text/html,<script>alert(1)</script>">`, `<img>`},
+		{"mailto image", `<img src="mailto:a@codonex.com">`, `<img>`},
+		{"safe urls", `<a href="mailto:info@codonex.com">m</a><img src="https://codonex.com/a.png">`,
+			`<a href="mailto:info@codonex.com" rel="nofollow noopener noreferrer">m</a><img src="https://codonex.com/a.png">`},
+		{"relative url with colon", `<a href="/search?q=a:b">s</a>`, `<a href="/search?q=a:b" rel="nofollow noopener noreferrer">s</a>`},
+		{"attribute quotes escaped", `<a title='x" onmouseover="alert(1)'>t</a>`, `<a title="x&#34; onmouseover=&#34;alert(1)" rel="nofollow noopener noreferrer">t</a>`},
+		{"unclosed tags", `<p><em>open`, `<p><em>open</em></p>`},
+		{"misnested tags", `<p><b>bold</p>text</b>`, `<p><b>bold</b></p>text`},
+		{"stray end tag", `</div>text</p>`, `text`},
+		{"comment", `a<!-- <script>alert(1)</script> -->b`, `ab`},
+		{"conditional comment", `<!--[if IE]><script>alert(1)</script><![endif]-->ok`, `ok`},
+		{"noscript breakout", `<noscript><p title="</noscript><img src=x onerror=alert(1)>">`, `<img src="x">&#34;&gt;`},
+		{"text escaped", `1 < 2 & "3"`, `1 &lt; 2 &amp; &#34;3&#34;`},
+		{"code class", `<code class="language-go">x</code><code class="evil">y</code>`, `<code class="language-go">x</code><code>y</code>`},
+		{"numeric attributes", `<td colspan="2" rowspan="x">c</td>`, `<td colspan="2">c</td>`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SanitizeHTML(test.input); got != test.want {
+				t.Errorf("SanitizeHTML(%q)\n got %q\nwant %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderDetailMarkdown(t *testing.T) {
+	got, err := RenderDetail("[x](JavaScript:void) <img src=x onerror=alert(1)>", FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<p><a rel="nofollow noopener noreferrer">x</a> <img src="x"></p>` + "\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -2,31 +2,107 @@ package main
 
 import (
 	"net/http"
+
+	"github.com/sirupsen/logrus"
 )
 
 // AddRoutes for api creates routes
 func (app *App) AddRoutes() {
+	app.Router.Use(requestIDMiddleware)
+	app.Router.Use(app.authMiddleware)
+	if conf := app.conf; conf != nil && (conf.Validation.Requests || conf.Validation.Responses) {
+		validator, err := newSpecValidator(openAPISpec)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to load the OpenAPI spec, validation is disabled")
+		} else {
+			app.Router.Use(app.validationMiddleware(validator, conf.Validation))
+		}
+	}
 
 	//Project API
 	app.AddRoute("POST", "/project", app.AddProjectItem)
 	app.AddRoute("GET", "/project", app.GetProjectItems)
+	app.AddRoute("POST", "/project/bulk", app.BulkProjects)
+	app.AddRoute("GET", "/project/timeline", app.GetProjectTimeline)
+	app.AddRoute("GET", "/project/by-slug/{slug}", app.FindProjectItemBySlug)
 	app.AddRoute("GET", "/project/{id}", app.FindProjectItem)
+	app.AddRoute("PUT", "/project/{id}", app.UpdateProjectItem)
+	app.AddRoute("GET", "/project/{id}/translations", app.GetProjectTranslations)
+	app.AddRoute("PUT", "/project/{id}/translations/{locale}", app.PutProjectTranslation)
+	app.AddRoute("DELETE", "/project/{id}/translations/{locale}", app.DeleteProjectTranslation)
+	app.AddRoute("PUT", "/project/{id}/news/{news_id}", app.LinkNewsToProject)
+	app.AddRoute("DELETE", "/project/{id}/news/{news_id}", app.UnlinkNewsFromProject)
 	app.AddRoute("DELETE", "/project/{id}", app.DeleteProjectItem)
+	app.AddRoute("GET", "/project/{id}/revisions", app.GetProjectRevisions)
+	app.AddRoute("GET", "/project/{id}/revisions/diff", app.GetProjectRevisionDiff)
+	app.AddRoute("GET", "/project/{id}/revisions/{rev:[0-9]+}", app.GetProjectRevision)
+	app.AddRoute("POST", "/project/{id}/revisions/{rev:[0-9]+}/revert", app.RevertProjectItem)
+	app.AddRoute("POST", "/project/{id}/restore", app.RestoreProjectItem)
 
 	//News API
 	app.AddRoute("POST", "/news", app.AddNewsItem)
 	app.AddRoute("GET", "/news", app.GetNewsItems)
+	app.AddRoute("POST", "/news/bulk", app.BulkNews)
+	app.AddRawRoute("GET", "/news/feed.rss", app.GetNewsRSS)
+	app.AddRawRoute("GET", "/news/feed.atom", app.GetNewsAtom)
+	app.AddRoute("GET", "/news/by-slug/{slug}", app.FindNewsItemBySlug)
 	app.AddRoute("GET", "/news/{id}", app.FindNewsItem)
+	app.AddRoute("PUT", "/news/{id}", app.UpdateNewsItem)
+	app.AddRawRoute("GET", "/news/{id}/image", app.GetNewsImage)
+	app.AddRoute("PUT", "/news/{id}/tags", app.SetNewsTags)
+	app.AddRoute("PUT", "/news/{id}/categories", app.SetNewsCategories)
+	app.AddRoute("PUT", "/news/{id}/projects/{project_id}", app.LinkProjectToNews)
+	app.AddRoute("DELETE", "/news/{id}/projects/{project_id}", app.UnlinkProjectFromNews)
+	app.AddRoute("GET", "/news/{id}/translations", app.GetNewsTranslations)
+	app.AddRoute("PUT", "/news/{id}/translations/{locale}", app.PutNewsTranslation)
+	app.AddRoute("DELETE", "/news/{id}/translations/{locale}", app.DeleteNewsTranslation)
 	app.AddRoute("DELETE", "/news/{id}", app.DeleteNewsItem)
+	app.AddRoute("GET", "/news/{id}/revisions", app.GetNewsRevisions)
+	app.AddRoute("GET", "/news/{id}/revisions/diff", app.GetNewsRevisionDiff)
+	app.AddRoute("GET", "/news/{id}/revisions/{rev:[0-9]+}", app.GetNewsRevision)
+	app.AddRoute("POST", "/news/{id}/revisions/{rev:[0-9]+}/revert", app.RevertNewsItem)
+	app.AddRoute("POST", "/news/{id}/restore", app.RestoreNewsItem)
+	app.AddRoute("POST", "/news/{id}/publish", app.PublishNewsItem)
+	app.AddRoute("POST", "/news/{id}/unpublish", app.UnpublishNewsItem)
+	app.AddRoute("POST", "/news/{id}/archive", app.ArchiveNewsItem)
+
+	//Tag and Category API
+	app.AddRoute("GET", "/tag", app.GetTags)
+	app.AddRoute("POST", "/tag", app.AddTag)
+	app.AddRoute("GET", "/tag/cloud", app.GetTagCloud)
+	app.AddRoute("DELETE", "/tag/{id}", app.DeleteTag)
+	app.AddRoute("GET", "/category", app.GetCategories)
+	app.AddRoute("POST", "/category", app.AddCategory)
+	app.AddRoute("GET", "/category/counts", app.GetCategoryCounts)
+	app.AddRoute("DELETE", "/category/{id}", app.DeleteCategory)
+
+	//Project metadata API
+	app.AddRoute("GET", "/client", app.GetClients)
+	app.AddRoute("GET", "/technology", app.GetTechnologies)
 
 	//Job API
 	app.AddRoute("POST", "/job", app.AddJobApplications)
 	app.AddRoute("GET", "/job", app.GetJobApplications)
+	app.AddRoute("GET", "/job/form-token", app.GetJobFormToken)
+	app.AddRoute("GET", "/job/rejections", app.GetJobRejections)
+	app.AddRawRoute("GET", "/job/export", app.ExportJobApplications)
 	app.AddRoute("GET", "/job/{id}", app.FindJobApplicationByID)
 	app.AddRoute("DELETE", "/job/{id}", app.DeleteJob)
+	app.AddRoute("POST", "/job/{id}/restore", app.RestoreJob)
+
+	//Trash API
+	app.AddRoute("GET", "/trash", app.GetTrash)
+
+	//Audit API
+	app.AddRoute("GET", "/audit", app.GetAuditLog)
 
 	//Relation API
 	app.AddRoute("POST", "/relation", app.AddRelation)
+	app.AddRoute("POST", "/relation/bulk", app.BulkRelations)
+
+	//API documentation
+	app.AddRawRoute("GET", "/openapi.json", app.GetOpenAPISpec)
+	app.AddRawRoute("GET", "/docs", app.GetAPIDocs)
 
 	//Health Check Status
 	app.AddRoute("GET", "/health", app.HealthCheck)
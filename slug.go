@@ -0,0 +1,182 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+// Resource types that own slugs
+const (
+	SlugNews    = "news"
+	SlugProject = "project"
+)
+
+const maxSlugLength = 80
+
+var slugReplacer = strings.NewReplacer(
+	"ı", "i", "İ", "i", "I", "i",
+	"ş", "s", "Ş", "s",
+	"ğ", "g", "Ğ", "g",
+	"ü", "u", "Ü", "u",
+	"ö", "o", "Ö", "o",
+	"ç", "c", "Ç", "c",
+	"â", "a", "Â", "a",
+	"î", "i", "Î", "i",
+	"û", "u", "Û", "u",
+)
+
+// Slugify converts a title into a lowercase, url safe slug.
+// Turkish letters are transliterated, e.g. "Çağrı Merkezi Açıldı" becomes "cagri-merkezi-acildi".
+func Slugify(title string) string {
+	title = strings.ToLower(slugReplacer.Replace(title))
+
+	var builder strings.Builder
+	dash := false
+	for _, char := range title {
+		if char < unicode.MaxASCII && (unicode.IsLetter(char) || unicode.IsDigit(char)) {
+			builder.WriteRune(char)
+			dash = false
+			continue
+		}
+		if !dash && builder.Len() > 0 {
+			builder.WriteRune('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimRight(builder.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		slug = "item"
+	}
+	return slug
+}
+
+// slugTable returns the table holding slugs for a resource type
+func slugTable(resourceType string) string {
+	if resourceType == SlugProject {
+		return "project"
+	}
+	return "news_item"
+}
+
+// uniqueSlug returns a slug for title that is not used by another item nor
+// reserved as a redirect. excludeID is the item being renamed, 0 on create.
+func uniqueSlug(db dbExecutor, resourceType string, title string, excludeID int) (string, error) {
+	base := Slugify(title)
+	sql := "SELECT EXISTS(SELECT 1 FROM " + slugTable(resourceType) + " WHERE slug=$1 AND uid<>$2) " +
+		"OR EXISTS(SELECT 1 FROM slug_redirect WHERE resource_type=$3 AND slug=$1 AND target_uid<>$2)"
+
+	slug := base
+	for suffix := 2; ; suffix++ {
+		var taken bool
+		if err := db.QueryRow(sql, slug, excludeID, resourceType).Scan(&taken); err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, suffix)
+	}
+}
+
+// maxSlugAttempts bounds the retries of a write that lost its slug to a
+// concurrent writer
+const maxSlugAttempts = 5
+
+// writeWithSlug picks a slug and runs write with it. A concurrent writer can
+// take the slug between the check of pick and the write, which then fails on
+// the unique index and is retried with a new slug. Each attempt runs in a
+// savepoint, so the failure does not abort the transaction tx.
+func writeWithSlug(tx dbExecutor, pick func() (string, error), write func(slug string) error) (string, error) {
+	for attempt := 1; ; attempt++ {
+		if _, err := tx.Exec("SAVEPOINT slug_write"); err != nil {
+			return "", err
+		}
+		slug, err := pick()
+		if err == nil {
+			err = write(slug)
+		}
+		if err == nil {
+			_, err = tx.Exec("RELEASE SAVEPOINT slug_write")
+			return slug, err
+		}
+		if !isSlugConflict(err) || attempt == maxSlugAttempts {
+			return "", err
+		}
+		if _, err = tx.Exec("ROLLBACK TO SAVEPOINT slug_write; RELEASE SAVEPOINT slug_write"); err != nil {
+			return "", err
+		}
+	}
+}
+
+// isSlugConflict reports whether err is a unique violation of a slug
+func isSlugConflict(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	if !ok || pqErr.Code != "23505" {
+		return false
+	}
+	switch pqErr.Constraint {
+	case "news_item_slug_idx", "project_slug_idx", "slug_redirect_pkey":
+		return true
+	}
+	return false
+}
+
+// renameSlug gives an item a new slug for its new title and keeps the old one
+// as a redirect. It returns the slug the item ends up with.
+func renameSlug(db dbExecutor, resourceType string, id int, currentSlug string, title string) (string, error) {
+	if Slugify(title) == currentSlug {
+		return currentSlug, nil
+	}
+	slug, err := uniqueSlug(db, resourceType, title, id)
+	if err != nil || slug == currentSlug {
+		return currentSlug, err
+	}
+
+	sql := "INSERT INTO slug_redirect(resource_type,slug,target_uid,created) VALUES($1,$2,$3,now()) " +
+		"ON CONFLICT (resource_type,slug) DO UPDATE SET target_uid=EXCLUDED.target_uid"
+	if _, err = db.Exec(sql, resourceType, currentSlug, id); err != nil {
+		return currentSlug, err
+	}
+	// The item may be taking back one of its own old slugs
+	if _, err = db.Exec("DELETE FROM slug_redirect WHERE resource_type=$1 AND slug=$2", resourceType, slug); err != nil {
+		return currentSlug, err
+	}
+	return slug, nil
+}
+
+// redirectSlug redirects to the current slug if slug is an old slug of an
+// item. visibleSQL restricts the item, n for news and p for projects, to the
+// ones the caller may see, so the new slug of a hidden item is not revealed.
+// It returns false when there is nothing to redirect to.
+func (app *App) redirectSlug(writer http.ResponseWriter, req *http.Request, resourceType string, slug string, visibleSQL string) bool {
+	alias := "n"
+	if resourceType == SlugProject {
+		alias = "p"
+	}
+	sql := "SELECT " + alias + ".slug FROM slug_redirect r JOIN " + slugTable(resourceType) + " " + alias + " ON " + alias + ".uid=r.target_uid " +
+		"WHERE r.resource_type=$1 AND r.slug=$2 AND " + visibleSQL
+	var current string
+	err := app.db.QueryRow(sql, resourceType, slug).Scan(&current)
+	if err == dbsql.ErrNoRows {
+		return false
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed find slug")
+		return true
+	}
+	target := "/" + resourceType + "/by-slug/" + current
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+	http.Redirect(writer, req, target, http.StatusMovedPermanently)
+	return true
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Çağrı Merkezi Açıldı", "cagri-merkezi-acildi"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"İSTANBUL'da Yeni Ofis", "istanbul-da-yeni-ofis"},
+		{"2026 Q1 Results", "2026-q1-results"},
+		{"!!!", "item"},
+		{"- / -", "item"},
+		{"日本", "item"},
+		{strings.Repeat("a", 79) + " b", strings.Repeat("a", 79)},
+	}
+	for _, test := range tests {
+		if got := Slugify(test.title); got != test.want {
+			t.Errorf("Slugify(%q) = %q, want %q", test.title, got, test.want)
+		}
+	}
+}
+
+func TestIsSlugConflict(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&pq.Error{Code: "23505", Constraint: "news_item_slug_idx"}, true},
+		{&pq.Error{Code: "23505", Constraint: "slug_redirect_pkey"}, true},
+		{&pq.Error{Code: "23505", Constraint: "api_key_name_key"}, false},
+		{&pq.Error{Code: "23503", Constraint: "project_slug_idx"}, false},
+		{errors.New("connection reset"), false},
+	}
+	for _, test := range tests {
+		if got := isSlugConflict(test.err); got != test.want {
+			t.Errorf("isSlugConflict(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
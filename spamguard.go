@@ -0,0 +1,434 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SpamConfig is config struct for job application abuse protection
+type SpamConfig struct {
+	// requests allowed per client ip within the window
+	IPLimit RateLimit `yaml:"ip_limit"`
+	// applications allowed per email address within the window
+	EmailLimit RateLimit `yaml:"email_limit"`
+	// a form submitted faster than this after the token was issued is rejected
+	MinFillTime time.Duration `yaml:"min_fill_time"`
+	// form tokens older than this are rejected
+	MaxFormAge time.Duration `yaml:"max_form_age"`
+	// secret used to sign form tokens, generated at startup when empty. Set it
+	// when several instances serve the form so their tokens verify on each other.
+	FormSecret     string `yaml:"form_secret" envconfig:"FORM_SECRET" secret:"true"`
+	FormSecretFile string `yaml:"form_secret_file" envconfig:"FORM_SECRET_FILE"`
+	// use X-Forwarded-For / X-Real-IP for the client ip
+	TrustProxyHeaders bool `yaml:"trust_proxy_headers"`
+	// additional disposable email domains to block
+	DisposableDomains []string      `yaml:"disposable_domains"`
+	Captcha           CaptchaConfig `yaml:"captcha"`
+}
+
+// RateLimit is a number of requests allowed within a window
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Window   time.Duration `yaml:"window"`
+}
+
+// CaptchaConfig is config struct for captcha verification
+type CaptchaConfig struct {
+	// none, recaptcha, hcaptcha or fake
+	Provider   string `yaml:"provider"`
+	Secret     string `yaml:"secret" envconfig:"CAPTCHA_SECRET" secret:"true"`
+	SecretFile string `yaml:"secret_file" envconfig:"CAPTCHA_SECRET_FILE"`
+	VerifyURL  string `yaml:"verify_url"`
+	// token accepted by the fake provider
+	FakeToken string `yaml:"fake_token"`
+}
+
+// FormTokenResponse response struct
+//
+//swagger:response FormTokenResponse
+type FormTokenResponse struct {
+	Token       string `json:"form_token"`
+	MinFillTime string `json:"min_fill_time"`
+}
+
+// JobRejectionResponse response struct
+//
+//swagger:response JobRejectionResponse
+type JobRejectionResponse struct {
+	ID      int       `json:"id"`
+	IP      string    `json:"ip"`
+	Email   string    `json:"email"`
+	Reason  string    `json:"reason"`
+	Payload string    `json:"payload"`
+	Created time.Time `json:"created"`
+}
+
+// Rejection describes why a submission was refused
+type Rejection struct {
+	Status     int
+	Reason     string
+	RetryAfter time.Duration
+}
+
+var builtinDisposableDomains = []string{
+	"10minutemail.com", "discard.email", "dispostable.com", "fakeinbox.com",
+	"getnada.com", "guerrillamail.com", "maildrop.cc", "mailinator.com",
+	"mintemail.com", "mohmal.com", "sharklasers.com", "temp-mail.org",
+	"tempmail.com", "throwawaymail.com", "trashmail.com", "yopmail.com",
+}
+
+// CaptchaVerifier verifies captcha responses
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token string, remoteIP string) (bool, error)
+}
+
+// NewCaptchaVerifier creates the verifier for the configured provider.
+// It returns nil when captcha verification is disabled.
+func NewCaptchaVerifier(conf CaptchaConfig) (CaptchaVerifier, error) {
+	switch conf.Provider {
+	case "", "none":
+		return nil, nil
+	case "fake":
+		return &FakeCaptchaVerifier{Token: conf.FakeToken}, nil
+	case "recaptcha":
+		return newSiteVerifyCaptcha(conf, "https://www.google.com/recaptcha/api/siteverify"), nil
+	case "hcaptcha":
+		return newSiteVerifyCaptcha(conf, "https://hcaptcha.com/siteverify"), nil
+	}
+	return nil, fmt.Errorf("unknown captcha provider %q", conf.Provider)
+}
+
+// FakeCaptchaVerifier accepts a single fixed token. It is meant for local
+// development and tests only.
+type FakeCaptchaVerifier struct {
+	Token string
+}
+
+// Verify verifies the captcha token
+func (v *FakeCaptchaVerifier) Verify(ctx context.Context, token string, remoteIP string) (bool, error) {
+	return token != "" && token == v.Token, nil
+}
+
+// siteVerifyCaptcha verifies tokens with the siteverify api shared by
+// reCAPTCHA and hCaptcha
+type siteVerifyCaptcha struct {
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+func newSiteVerifyCaptcha(conf CaptchaConfig, defaultURL string) *siteVerifyCaptcha {
+	verifyURL := conf.VerifyURL
+	if verifyURL == "" {
+		verifyURL = defaultURL
+	}
+	return &siteVerifyCaptcha{secret: conf.Secret, verifyURL: verifyURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Verify verifies the captcha token
+func (v *siteVerifyCaptcha) Verify(ctx context.Context, token string, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	form := url.Values{"secret": {v.secret}, "response": {token}, "remoteip": {remoteIP}}
+	req, err := http.NewRequest(http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := v.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
+
+// RateLimiter is an in memory fixed window rate limiter
+type RateLimiter struct {
+	mutex   sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter creates a new rate limiter
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{windows: map[string]*rateWindow{}}
+}
+
+// Allow counts a request for key and reports whether it is within the limit.
+// When it is not, the time until the window resets is returned.
+func (l *RateLimiter) Allow(key string, limit RateLimit) (bool, time.Duration) {
+	if limit.Requests <= 0 || limit.Window <= 0 {
+		return true, 0
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	window, ok := l.windows[key]
+	if !ok || now.Sub(window.start) >= limit.Window {
+		window = &rateWindow{start: now}
+		l.windows[key] = window
+	}
+	window.count++
+	if window.count > limit.Requests {
+		return false, limit.Window - now.Sub(window.start)
+	}
+	return true, 0
+}
+
+// Cleanup removes windows that started more than maxAge ago
+func (l *RateLimiter) Cleanup(maxAge time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for key, window := range l.windows {
+		if time.Since(window.start) > maxAge {
+			delete(l.windows, key)
+		}
+	}
+}
+
+// SpamGuard protects the public job application form against bots
+type SpamGuard struct {
+	conf       SpamConfig
+	limiter    *RateLimiter
+	captcha    CaptchaVerifier
+	disposable map[string]bool
+
+	// limitsMutex guards the rate limits of conf, they change on config reload
+	limitsMutex sync.RWMutex
+}
+
+// NewSpamGuard creates a new spam guard
+func NewSpamGuard(conf SpamConfig) (*SpamGuard, error) {
+	captcha, err := NewCaptchaVerifier(conf.Captcha)
+	if err != nil {
+		return nil, err
+	}
+	disposable := map[string]bool{}
+	for _, domain := range builtinDisposableDomains {
+		disposable[domain] = true
+	}
+	for _, domain := range conf.DisposableDomains {
+		disposable[strings.ToLower(domain)] = true
+	}
+	if conf.FormSecret == "" {
+		secret := make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			return nil, err
+		}
+		conf.FormSecret = base64.RawURLEncoding.EncodeToString(secret)
+		logrus.Warn("No spam.form_secret configured, form tokens are signed with a secret generated for this process")
+	}
+	return &SpamGuard{conf: conf, limiter: NewRateLimiter(), captcha: captcha, disposable: disposable}, nil
+}
+
+// SetRateLimits replaces the per ip and per email rate limits
+func (g *SpamGuard) SetRateLimits(ipLimit RateLimit, emailLimit RateLimit) {
+	g.limitsMutex.Lock()
+	defer g.limitsMutex.Unlock()
+	g.conf.IPLimit, g.conf.EmailLimit = ipLimit, emailLimit
+}
+
+// rateLimits returns the per ip and per email rate limits
+func (g *SpamGuard) rateLimits() (RateLimit, RateLimit) {
+	g.limitsMutex.RLock()
+	defer g.limitsMutex.RUnlock()
+	return g.conf.IPLimit, g.conf.EmailLimit
+}
+
+// ClientIP returns the ip address of the client
+func (g *SpamGuard) ClientIP(req *http.Request) string {
+	if g.conf.TrustProxyHeaders {
+		if forwarded := req.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+		if realIP := req.Header.Get("X-Real-IP"); realIP != "" {
+			return realIP
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// IssueFormToken creates a signed token carrying the time the form was served
+func (g *SpamGuard) IssueFormToken() string {
+	issued := strconv.FormatInt(time.Now().Unix(), 10)
+	return issued + "." + g.sign(issued)
+}
+
+func (g *SpamGuard) sign(value string) string {
+	mac := hmac.New(sha256.New, []byte(g.conf.FormSecret))
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// checkFormToken verifies the signature and fill time of a form token
+func (g *SpamGuard) checkFormToken(token string) error {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(g.sign(parts[0])), []byte(parts[1])) {
+		return errors.New("invalid form token")
+	}
+	issuedUnix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return errors.New("invalid form token")
+	}
+	elapsed := time.Since(time.Unix(issuedUnix, 0))
+	if elapsed < g.conf.MinFillTime {
+		return errors.New("form submitted too fast")
+	}
+	if g.conf.MaxFormAge > 0 && elapsed > g.conf.MaxFormAge {
+		return errors.New("form token expired")
+	}
+	return nil
+}
+
+// IsDisposableEmail reports whether the email belongs to a disposable domain
+func (g *SpamGuard) IsDisposableEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	return g.disposable[strings.ToLower(strings.TrimSpace(email[at+1:]))]
+}
+
+// CheckIP applies the per ip rate limit
+func (g *SpamGuard) CheckIP(ip string) *Rejection {
+	ipLimit, _ := g.rateLimits()
+	if ok, retry := g.limiter.Allow("ip:"+ip, ipLimit); !ok {
+		return &Rejection{Status: http.StatusTooManyRequests, Reason: "ip rate limit exceeded", RetryAfter: retry}
+	}
+	return nil
+}
+
+// CheckApplication runs the content based checks on a job application
+func (g *SpamGuard) CheckApplication(ctx context.Context, request *JobRequest, ip string) *Rejection {
+	if request.Website != "" {
+		return &Rejection{Status: http.StatusBadRequest, Reason: "honeypot field filled"}
+	}
+	if err := g.checkFormToken(request.FormToken); err != nil {
+		return &Rejection{Status: http.StatusBadRequest, Reason: err.Error()}
+	}
+	if g.IsDisposableEmail(request.Email) {
+		return &Rejection{Status: http.StatusBadRequest, Reason: "disposable email domain"}
+	}
+	if g.captcha != nil {
+		ok, err := g.captcha.Verify(ctx, request.CaptchaToken, ip)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to verify captcha")
+		}
+		if !ok {
+			return &Rejection{Status: http.StatusBadRequest, Reason: "captcha verification failed"}
+		}
+	}
+	return nil
+}
+
+// CheckEmail applies the per email rate limit. It runs after the application
+// passed validation and the other checks, so that invalid posts do not use up
+// the quota of the address they name.
+func (g *SpamGuard) CheckEmail(email string) *Rejection {
+	_, emailLimit := g.rateLimits()
+	if ok, retry := g.limiter.Allow("email:"+strings.ToLower(strings.TrimSpace(email)), emailLimit); !ok {
+		return &Rejection{Status: http.StatusTooManyRequests, Reason: "email rate limit exceeded", RetryAfter: retry}
+	}
+	return nil
+}
+
+// cleanupRateLimits drops expired rate limit windows
+func (g *SpamGuard) cleanupRateLimits(ctx context.Context) {
+	ipLimit, emailLimit := g.rateLimits()
+	maxAge := ipLimit.Window
+	if emailLimit.Window > maxAge {
+		maxAge = emailLimit.Window
+	}
+	g.limiter.Cleanup(maxAge)
+}
+
+// maxRejectionPayload is the number of bytes of a rejected body kept for review
+const maxRejectionPayload = 8 << 10
+
+// rejectJobApplication logs the rejected submission and renders the error.
+// Submissions refused by a check are stored for review. Rate limited ones are
+// only logged, a flood would otherwise write a row per request.
+func (app *App) rejectJobApplication(writer http.ResponseWriter, ip string, email string, payload []byte, rejection *Rejection) {
+	logrus.WithFields(logrus.Fields{"ip": ip, "email": email, "reason": rejection.Reason}).Warn("Job application rejected")
+
+	if rejection.Status != http.StatusTooManyRequests {
+		if len(payload) > maxRejectionPayload {
+			payload = payload[:maxRejectionPayload]
+		}
+		sql := "INSERT INTO job_application_rejection(ip,email,reason,payload,created) VALUES($1,$2,$3,$4,$5)"
+		if _, err := app.db.Exec(sql, ip, email, rejection.Reason, strings.ToValidUTF8(string(payload), ""), time.Now()); err != nil {
+			logrus.WithError(err).Error("Failed to log rejected job application")
+		}
+	}
+
+	if rejection.RetryAfter > 0 {
+		writer.Header().Set("Retry-After", strconv.Itoa(int(rejection.RetryAfter.Seconds())+1))
+	}
+	app.RenderErrorResponse(writer, rejection.Status, errors.New(rejection.Reason), "Job application rejected")
+}
+
+// GetJobFormToken issues a form token to be sent back with the job application
+func (app *App) GetJobFormToken(writer http.ResponseWriter, req *http.Request) {
+	app.RenderJson(writer, http.StatusOK, FormTokenResponse{
+		Token:       app.spamGuard.IssueFormToken(),
+		MinFillTime: app.spamGuard.conf.MinFillTime.String(),
+	})
+}
+
+// GetJobRejections lists rejected job applications for review
+func (app *App) GetJobRejections(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireAdmin(writer, req) {
+		return
+	}
+	sql := "SELECT id,ip,email,reason,payload,created FROM job_application_rejection ORDER BY id DESC LIMIT 500"
+	rows, err := app.db.Query(sql)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get rejected job applications")
+		return
+	}
+	defer rows.Close()
+
+	rejections := []JobRejectionResponse{}
+	for rows.Next() {
+		resp := JobRejectionResponse{}
+		if err = rows.Scan(&resp.ID, &resp.IP, &resp.Email, &resp.Reason, &resp.Payload, &resp.Created); err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get rejected job applications")
+			return
+		}
+		rejections = append(rejections, resp)
+	}
+	app.RenderJson(writer, http.StatusOK, rejections)
+}
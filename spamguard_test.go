@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestSpamGuard(t *testing.T, conf SpamConfig) *SpamGuard {
+	conf.FormSecret = "test-secret"
+	guard, err := NewSpamGuard(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return guard
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter()
+	limit := RateLimit{Requests: 2, Window: time.Hour}
+	for i := 1; i <= 2; i++ {
+		if ok, _ := limiter.Allow("ip:a", limit); !ok {
+			t.Fatalf("request %d refused", i)
+		}
+	}
+	ok, retry := limiter.Allow("ip:a", limit)
+	if ok || retry <= 0 || retry > time.Hour {
+		t.Errorf("third request: ok %v retry %s", ok, retry)
+	}
+	if ok, _ = limiter.Allow("ip:b", limit); !ok {
+		t.Error("other key shares the window")
+	}
+	for i := 0; i < 10; i++ {
+		if ok, _ = limiter.Allow("ip:a", RateLimit{}); !ok {
+			t.Fatal("refused without a limit")
+		}
+	}
+
+	short := RateLimit{Requests: 1, Window: 20 * time.Millisecond}
+	limiter.Allow("ip:c", short)
+	if ok, _ = limiter.Allow("ip:c", short); ok {
+		t.Error("second request allowed within the window")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if ok, _ = limiter.Allow("ip:c", short); !ok {
+		t.Error("request refused after the window reset")
+	}
+
+	limiter.Cleanup(time.Hour)
+	if len(limiter.windows) != 3 {
+		t.Errorf("cleanup removed current windows, %d left", len(limiter.windows))
+	}
+	limiter.Cleanup(0)
+	if len(limiter.windows) != 0 {
+		t.Errorf("%d windows left after cleanup", len(limiter.windows))
+	}
+}
+
+func TestFormToken(t *testing.T) {
+	guard := newTestSpamGuard(t, SpamConfig{MaxFormAge: time.Hour})
+	signed := func(issued time.Time) string {
+		value := strconv.FormatInt(issued.Unix(), 10)
+		return value + "." + guard.sign(value)
+	}
+	tests := []struct {
+		name    string
+		token   string
+		minFill time.Duration
+		wantErr string
+	}{
+		{"issued token", guard.IssueFormToken(), 0, ""},
+		{"filled in time", signed(time.Now().Add(-time.Minute)), 10 * time.Second, ""},
+		{"too fast", guard.IssueFormToken(), 10 * time.Second, "form submitted too fast"},
+		{"expired", signed(time.Now().Add(-2 * time.Hour)), 0, "form token expired"},
+		{"empty", "", 0, "invalid form token"},
+		{"no signature", strconv.FormatInt(time.Now().Unix(), 10), 0, "invalid form token"},
+		{"tampered time", strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10) + "." + guard.sign("1"), 0, "invalid form token"},
+		{"signed garbage", "abc." + guard.sign("abc"), 0, "invalid form token"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			guard.conf.MinFillTime = test.minFill
+			err := guard.checkFormToken(test.token)
+			if test.wantErr == "" && err != nil {
+				t.Errorf("got %v", err)
+			}
+			if test.wantErr != "" && (err == nil || err.Error() != test.wantErr) {
+				t.Errorf("got %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+
+	other := newTestSpamGuard(t, SpamConfig{})
+	other.conf.FormSecret = "other-secret"
+	if err := other.checkFormToken(guard.IssueFormToken()); err == nil {
+		t.Error("token verified with another secret")
+	}
+}
+
+func TestIsDisposableEmail(t *testing.T) {
+	guard := newTestSpamGuard(t, SpamConfig{DisposableDomains: []string{"Spam.Example"}})
+	tests := map[string]bool{
+		"someone@mailinator.com":     true,
+		"someone@YOPMAIL.com":        true,
+		"someone@spam.example":       true,
+		"someone@codonex.com":        false,
+		"someone@sub.mailinator.com": false,
+		"not an email":               false,
+		"":                           false,
+	}
+	for email, want := range tests {
+		if got := guard.IsDisposableEmail(email); got != want {
+			t.Errorf("IsDisposableEmail(%q) = %v, want %v", email, got, want)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trust      bool
+		remoteAddr string
+		header     map[string]string
+		want       string
+	}{
+		{"remote address", false, "192.0.2.1:5000", nil, "192.0.2.1"},
+		{"ipv6 remote address", false, "[2001:db8::1]:5000", nil, "2001:db8::1"},
+		{"remote address without port", false, "192.0.2.1", nil, "192.0.2.1"},
+		{"proxy headers ignored", false, "192.0.2.1:5000",
+			map[string]string{"X-Forwarded-For": "198.51.100.7", "X-Real-IP": "198.51.100.8"}, "192.0.2.1"},
+		{"first forwarded address", true, "192.0.2.1:5000",
+			map[string]string{"X-Forwarded-For": " 198.51.100.7 , 10.0.0.1", "X-Real-IP": "198.51.100.8"}, "198.51.100.7"},
+		{"real ip", true, "192.0.2.1:5000", map[string]string{"X-Real-IP": "198.51.100.8"}, "198.51.100.8"},
+		{"trusted without headers", true, "192.0.2.1:5000", nil, "192.0.2.1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			guard := newTestSpamGuard(t, SpamConfig{TrustProxyHeaders: test.trust})
+			req := httptest.NewRequest(http.MethodPost, "/job", nil)
+			req.RemoteAddr = test.remoteAddr
+			for key, value := range test.header {
+				req.Header.Set(key, value)
+			}
+			if got := guard.ClientIP(req); got != test.want {
+				t.Errorf("ClientIP = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckApplication(t *testing.T) {
+	guard := newTestSpamGuard(t, SpamConfig{Captcha: CaptchaConfig{Provider: "fake", FakeToken: "ok"}})
+	valid := func() *JobRequest {
+		return &JobRequest{Email: "someone@codonex.com", FormToken: guard.IssueFormToken(), CaptchaToken: "ok"}
+	}
+	tests := []struct {
+		name   string
+		change func(request *JobRequest)
+		want   string
+	}{
+		{"valid", func(request *JobRequest) {}, ""},
+		{"honeypot", func(request *JobRequest) { request.Website = "http://spam.example" }, "honeypot field filled"},
+		{"bad token", func(request *JobRequest) { request.FormToken = "x" }, "invalid form token"},
+		{"disposable", func(request *JobRequest) { request.Email = "someone@mailinator.com" }, "disposable email domain"},
+		{"captcha", func(request *JobRequest) { request.CaptchaToken = "wrong" }, "captcha verification failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := valid()
+			test.change(request)
+			rejection := guard.CheckApplication(context.Background(), request, "192.0.2.1")
+			if test.want == "" {
+				if rejection != nil {
+					t.Errorf("rejected: %s", rejection.Reason)
+				}
+				return
+			}
+			if rejection == nil || rejection.Reason != test.want || rejection.Status != http.StatusBadRequest {
+				t.Errorf("got %+v, want %s", rejection, test.want)
+			}
+		})
+	}
+}
+
+func TestRejectRateLimited(t *testing.T) {
+	// A rate limited rejection is not stored, the app has no database
+	app := &App{}
+	recorder := httptest.NewRecorder()
+	app.rejectJobApplication(recorder, "192.0.2.1", "", nil,
+		&Rejection{Status: http.StatusTooManyRequests, Reason: "ip rate limit exceeded", RetryAfter: 2500 * time.Millisecond})
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("Retry-After"); got != "3" {
+		t.Errorf("Retry-After %q, want 3", got)
+	}
+}
@@ -0,0 +1,459 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+// TermRequest request struct
+//
+//swagger:model TermRequest
+type TermRequest struct {
+	Name string `json:"name"`
+}
+
+// TermResponse response struct
+//
+//swagger:response TermResponse
+type TermResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// TermCountResponse response struct
+//
+//swagger:response TermCountResponse
+type TermCountResponse struct {
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Count int    `json:"count"`
+}
+
+// NewsTermsRequest request struct
+//
+//swagger:model NewsTermsRequest
+type NewsTermsRequest struct {
+	Names []string `json:"names"`
+}
+
+// taxonomy describes a term table linked many-to-many to news_item or project
+type taxonomy struct {
+	name      string
+	table     string
+	linkTable string
+	linkKey   string
+	// column of the link table referencing the owner, the owner table and its alias in queries
+	ownerKey   string
+	ownerTable string
+	ownerAlias string
+	// unknown terms are created on the fly when linking
+	autoCreate bool
+	// resource type of the terms in the audit log
+	auditType string
+}
+
+var (
+	tags = taxonomy{name: "tag", table: "tag", linkTable: "news_tag", linkKey: "tag_id",
+		ownerKey: "news_uid", ownerTable: "news_item", ownerAlias: "n", autoCreate: true, auditType: AuditTag}
+	categories = taxonomy{name: "category", table: "category", linkTable: "news_category", linkKey: "category_id",
+		ownerKey: "news_uid", ownerTable: "news_item", ownerAlias: "n", auditType: AuditCategory}
+	technologies = taxonomy{name: "technology", table: "technology", linkTable: "project_technology", linkKey: "technology_id",
+		ownerKey: "project_uid", ownerTable: "project", ownerAlias: "p", autoCreate: true, auditType: AuditTechnology}
+)
+
+// maxTermNameLength is the size of the name column of the term tables
+const maxTermNameLength = 64
+
+// termConflict is the error of a name whose slug belongs to a term with
+// another name, e.g. "C#" when "C++" exists, both slugify to "c"
+type termConflict struct {
+	name     string
+	existing string
+}
+
+func (e termConflict) Error() string {
+	return fmt.Sprintf("%q has the same slug as %q", e.name, e.existing)
+}
+
+// validateTermName checks a term name against the name column. Names without
+// latin letters or digits are rejected since they all slugify to "item".
+func validateTermName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if utf8.RuneCountInString(name) > maxTermNameLength {
+		return fmt.Errorf("name is longer than %d characters", maxTermNameLength)
+	}
+	if Slugify(name) == "item" && !strings.EqualFold(name, "item") {
+		return fmt.Errorf("name %q has no letters or digits", name)
+	}
+	return nil
+}
+
+// sameTermName reports whether two names with the same slug name the same
+// term, they may only differ in case and spacing
+func sameTermName(a string, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// hasTermSQL returns a condition matching owners linked to the term with slug ?
+func (t taxonomy) hasTermSQL() string {
+	return "EXISTS(SELECT 1 FROM " + t.linkTable + " l JOIN " + t.table + " x ON x.id=l." + t.linkKey +
+		" WHERE l." + t.ownerKey + "=" + t.ownerAlias + ".uid AND x.slug=?)"
+}
+
+// createTerm inserts a term, or returns the existing one with the same slug.
+// It fails with termConflict when that term has another name.
+func (t taxonomy) createTerm(db dbExecutor, name string) (TermResponse, error) {
+	name = strings.TrimSpace(name)
+	if err := validateTermName(name); err != nil {
+		return TermResponse{}, err
+	}
+	term := TermResponse{Name: name, Slug: Slugify(name)}
+	sql := "INSERT INTO " + t.table + "(name,slug,created) VALUES($1,$2,$3) " +
+		"ON CONFLICT (slug) DO UPDATE SET slug=EXCLUDED.slug RETURNING id,name"
+	if err := db.QueryRow(sql, term.Name, term.Slug, time.Now()).Scan(&term.ID, &term.Name); err != nil {
+		return TermResponse{}, err
+	}
+	if !sameTermName(term.Name, name) {
+		return TermResponse{}, termConflict{name: name, existing: term.Name}
+	}
+	return term, nil
+}
+
+// setTerms replaces the terms linked to an owner. Names are matched by slug.
+func (t taxonomy) setTerms(db dbExecutor, ownerID int, names []string) error {
+	if _, err := db.Exec("DELETE FROM "+t.linkTable+" WHERE "+t.ownerKey+"=$1", ownerID); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		var termID int
+		var err error
+		if t.autoCreate {
+			var term TermResponse
+			term, err = t.createTerm(db, name)
+			termID = term.ID
+		} else {
+			var existing string
+			err = db.QueryRow("SELECT id,name FROM "+t.table+" WHERE slug=$1", Slugify(name)).Scan(&termID, &existing)
+			if err != nil {
+				return fmt.Errorf("unknown %s %q", t.name, name)
+			}
+			if !sameTermName(existing, name) {
+				return termConflict{name: strings.TrimSpace(name), existing: existing}
+			}
+		}
+		if err != nil {
+			return err
+		}
+		sql := "INSERT INTO " + t.linkTable + "(" + t.ownerKey + "," + t.linkKey + ") VALUES($1,$2) ON CONFLICT DO NOTHING"
+		if _, err = db.Exec(sql, ownerID, termID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// terms loads the terms of the given owners, keyed by owner id
+func (t taxonomy) terms(db dbExecutor, ownerIDs []int) (map[int][]TermResponse, error) {
+	terms := map[int][]TermResponse{}
+	if len(ownerIDs) == 0 {
+		return terms, nil
+	}
+
+	sql := "SELECT l." + t.ownerKey + ",x.id,x.name,x.slug FROM " + t.linkTable + " l JOIN " + t.table + " x ON x.id=l." + t.linkKey +
+		" WHERE l." + t.ownerKey + " = ANY($1) ORDER BY x.name"
+	rows, err := db.Query(sql, intArray(ownerIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ownerID int
+		term := TermResponse{}
+		if err = rows.Scan(&ownerID, &term.ID, &term.Name, &term.Slug); err != nil {
+			return nil, err
+		}
+		terms[ownerID] = append(terms[ownerID], term)
+	}
+	return terms, rows.Err()
+}
+
+// attachNewsTerms fills the tags and categories of news items
+func attachNewsTerms(db dbExecutor, news []NewsResponse) error {
+	ids := make([]int, len(news))
+	for i := range news {
+		ids[i] = news[i].ID
+	}
+	newsTags, err := tags.terms(db, ids)
+	if err != nil {
+		return err
+	}
+	newsCategories, err := categories.terms(db, ids)
+	if err != nil {
+		return err
+	}
+	for i := range news {
+		news[i].Tags = newsTags[news[i].ID]
+		news[i].Categories = newsCategories[news[i].ID]
+	}
+	return nil
+}
+
+// setNewsTaxonomies stores the tags and categories sent with a news request.
+// Nil lists leave the current links untouched.
+func setNewsTaxonomies(db dbExecutor, newsID int, request NewsRequest) error {
+	if request.Tags != nil {
+		if err := tags.setTerms(db, newsID, request.Tags); err != nil {
+			return err
+		}
+	}
+	if request.Categories != nil {
+		if err := categories.setTerms(db, newsID, request.Categories); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTags lists all tags
+func (app *App) GetTags(writer http.ResponseWriter, req *http.Request) {
+	app.listTerms(writer, tags)
+}
+
+// AddTag adds a tag
+func (app *App) AddTag(writer http.ResponseWriter, req *http.Request) {
+	app.addTerm(writer, req, tags)
+}
+
+// DeleteTag deletes a tag and unlinks it from all news items
+func (app *App) DeleteTag(writer http.ResponseWriter, req *http.Request) {
+	app.deleteTerm(writer, req, tags)
+}
+
+// GetTagCloud counts the published news items of every tag
+func (app *App) GetTagCloud(writer http.ResponseWriter, req *http.Request) {
+	app.countTerms(writer, tags)
+}
+
+// GetCategories lists all categories
+func (app *App) GetCategories(writer http.ResponseWriter, req *http.Request) {
+	app.listTerms(writer, categories)
+}
+
+// AddCategory adds a category
+func (app *App) AddCategory(writer http.ResponseWriter, req *http.Request) {
+	app.addTerm(writer, req, categories)
+}
+
+// DeleteCategory deletes a category and unlinks it from all news items
+func (app *App) DeleteCategory(writer http.ResponseWriter, req *http.Request) {
+	app.deleteTerm(writer, req, categories)
+}
+
+// GetCategoryCounts counts the published news items of every category
+func (app *App) GetCategoryCounts(writer http.ResponseWriter, req *http.Request) {
+	app.countTerms(writer, categories)
+}
+
+// SetNewsTags replaces the tags of a news item
+func (app *App) SetNewsTags(writer http.ResponseWriter, req *http.Request) {
+	app.setNewsTerms(writer, req, tags)
+}
+
+// SetNewsCategories replaces the categories of a news item
+func (app *App) SetNewsCategories(writer http.ResponseWriter, req *http.Request) {
+	app.setNewsTerms(writer, req, categories)
+}
+
+func (app *App) listTerms(writer http.ResponseWriter, t taxonomy) {
+	rows, err := app.db.Query("SELECT id,name,slug FROM " + t.table + " ORDER BY name")
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get "+t.name+" list")
+		return
+	}
+	defer rows.Close()
+
+	terms := []TermResponse{}
+	for rows.Next() {
+		term := TermResponse{}
+		if err = rows.Scan(&term.ID, &term.Name, &term.Slug); err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get "+t.name+" list")
+			return
+		}
+		terms = append(terms, term)
+	}
+	app.RenderJson(writer, http.StatusOK, terms)
+}
+
+func (app *App) addTerm(writer http.ResponseWriter, req *http.Request, t taxonomy) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
+		return
+	}
+	var request TermRequest
+	if err = unmarshalRequest(req, reqBody, &request); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to convert json code")
+		return
+	}
+	if err = validateTermName(strings.TrimSpace(request.Name)); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Name is wrong")
+		return
+	}
+
+	term, err := t.createTerm(app.db, request.Name)
+	if conflict, ok := err.(termConflict); ok {
+		app.RenderErrorResponse(writer, http.StatusConflict, conflict, fmt.Sprintf("%s [%s] already exists with this slug", t.name, conflict.existing))
+		return
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to write "+t.name)
+		return
+	}
+	app.RenderJson(writer, http.StatusOK, term)
+}
+
+func (app *App) deleteTerm(writer http.ResponseWriter, req *http.Request, t taxonomy) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	params := mux.Vars(req)
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete "+t.name)
+		return
+	}
+	defer tx.Rollback()
+
+	// The owners lose the term, their representation changes
+	sql := "UPDATE " + t.ownerTable + " SET version=version+1 WHERE uid IN (SELECT " + t.ownerKey + " FROM " + t.linkTable + " WHERE " + t.linkKey + "=$1)"
+	if _, err = tx.Exec(sql, params["id"]); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete "+t.name)
+		return
+	}
+	before := TermResponse{}
+	err = tx.QueryRow("DELETE FROM "+t.table+" WHERE id=$1 RETURNING id,name,slug", params["id"]).Scan(&before.ID, &before.Name, &before.Slug)
+	if err == dbsql.ErrNoRows {
+		app.RenderErrorResponse(writer, http.StatusNotFound, NotFoundError, fmt.Sprintf("%s [%s] not found", t.name, params["id"]))
+		return
+	}
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: AuditDelete, resourceType: t.auditType, resourceID: before.ID, before: before})
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed delete "+t.name)
+		return
+	}
+	app.RenderJson(writer, http.StatusOK, nil)
+}
+
+func (app *App) countTerms(writer http.ResponseWriter, t taxonomy) {
+	sql := "SELECT x.name,x.slug,COUNT(n.uid) FROM " + t.table + " x " +
+		"JOIN " + t.linkTable + " l ON l." + t.linkKey + "=x.id " +
+		"JOIN news_item n ON n.uid=l.news_uid AND " + newsVisibleSQL +
+		" GROUP BY x.id,x.name,x.slug ORDER BY COUNT(n.uid) DESC, x.name"
+	rows, err := app.db.Query(sql)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed count "+t.name+" usage")
+		return
+	}
+	defer rows.Close()
+
+	counts := []TermCountResponse{}
+	for rows.Next() {
+		count := TermCountResponse{}
+		if err = rows.Scan(&count.Name, &count.Slug, &count.Count); err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed count "+t.name+" usage")
+			return
+		}
+		counts = append(counts, count)
+	}
+	app.RenderJson(writer, http.StatusOK, counts)
+}
+
+func (app *App) setNewsTerms(writer http.ResponseWriter, req *http.Request, t taxonomy) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	params := mux.Vars(req)
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
+		return
+	}
+	var request NewsTermsRequest
+	if err = unmarshalRequest(req, reqBody, &request); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to convert json code")
+		return
+	}
+
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news "+t.name)
+		return
+	}
+	defer tx.Rollback()
+
+	var newsID, version int
+	sql := "SELECT uid,version FROM news_item WHERE uid=$1 AND deleted_at IS NULL FOR UPDATE"
+	if err = tx.QueryRow(sql, params["id"]).Scan(&newsID, &version); err != nil {
+		app.RenderErrorResponse(writer, http.StatusNotFound, err, fmt.Sprintf("News item [%s] not found", params["id"]))
+		return
+	}
+	if !app.checkIfMatch(writer, req, version, true) {
+		return
+	}
+	before, err := t.terms(tx, []int{newsID})
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news "+t.name)
+		return
+	}
+	if err = t.setTerms(tx, newsID, request.Names); err != nil {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to update news "+t.name)
+		return
+	}
+	if err = bumpVersion(tx, "news_item", newsID); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news "+t.name)
+		return
+	}
+	terms, err := t.terms(tx, []int{newsID})
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news "+t.name)
+		return
+	}
+	err = app.recordAudit(tx, req, auditEntry{action: AuditUpdate, resourceType: AuditNews, resourceID: newsID,
+		before: map[string][]TermResponse{t.name: before[newsID]}, after: map[string][]TermResponse{t.name: terms[newsID]}})
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news "+t.name)
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed to update news "+t.name)
+		return
+	}
+
+	response := terms[newsID]
+	if response == nil {
+		response = []TermResponse{}
+	}
+	writer.Header().Set("ETag", versionETag(version+1, ""))
+	app.RenderJson(writer, http.StatusOK, response)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTermName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Go", false},
+		{"C++", false},
+		{"Yapay Zekâ", false},
+		{"item", false},
+		{strings.Repeat("ç", maxTermNameLength), false},
+		{strings.Repeat("ç", maxTermNameLength+1), true},
+		{"", true},
+		{"++", true},
+		{"日本語", true},
+	}
+	for _, test := range tests {
+		if err := validateTermName(test.name); (err != nil) != test.wantErr {
+			t.Errorf("validateTermName(%q) = %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestSameTermName(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{"Go", "go", true},
+		{"Machine  Learning", " machine learning", true},
+		{"C++", "C#", false},
+		{"C", "C++", false},
+	}
+	for _, test := range tests {
+		if Slugify(test.a) != Slugify(test.b) {
+			t.Fatalf("%q and %q do not share a slug", test.a, test.b)
+		}
+		if got := sameTermName(test.a, test.b); got != test.want {
+			t.Errorf("sameTermName(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	dbsql "database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// TrashConfig is config struct for soft deleted content
+type TrashConfig struct {
+	// deleted items are purged for good once they are older than this
+	PurgeAfter time.Duration `yaml:"purge_after"`
+	// how often the purge worker runs
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// TrashItemResponse response struct
+//
+//swagger:response TrashItemResponse
+type TrashItemResponse struct {
+	Type      string    `json:"type"`
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy string    `json:"deleted_by"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// trashable describes a soft deletable resource
+type trashable struct {
+	resource string
+	table    string
+	// expression used as the title of the item in the trash
+	titleSQL string
+	// resource type of the slug redirects pointing to the item, if any
+	slugType string
+	// revisions of the item, nil when it keeps none
+	revisions *versioned
+}
+
+var (
+	trashNews     = trashable{"news", "news_item", "news_title", SlugNews, &newsRevisions}
+	trashProjects = trashable{"project", "project", "project_name", SlugProject, &projectRevisions}
+	trashJobs     = trashable{"job", "job_application", "first_name || ' ' || last_name", "", nil}
+
+	trashables = []trashable{trashNews, trashProjects, trashJobs}
+)
+
+// purgeAfter returns the age after which deleted items are purged
+func (conf TrashConfig) purgeAfter() time.Duration {
+	if conf.PurgeAfter <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return conf.PurgeAfter
+}
+
+// purgeInterval returns how often the purge worker runs
+func (conf TrashConfig) purgeInterval() time.Duration {
+	if conf.PurgeInterval <= 0 {
+		return time.Hour
+	}
+	return conf.PurgeInterval
+}
+
+// softDeleteSQL marks an item of t as deleted by the user given as first parameter
+func softDeleteSQL(t trashable) string {
+	return "UPDATE " + t.table + " SET deleted_at=now(), deleted_by=$1, version=version+1 WHERE uid=$2 AND deleted_at IS NULL"
+}
+
+// softDelete moves an item to the trash. It renders the error response and
+// returns false when the item could not be deleted.
+func (app *App) softDelete(writer http.ResponseWriter, req *http.Request, t trashable) bool {
+	if !app.requireEditor(writer, req) {
+		return false
+	}
+	return app.changeTrashState(writer, req, t, AuditDelete, true, softDeleteSQL(t), app.authenticate(req).Name)
+}
+
+// restore takes an item back out of the trash
+func (app *App) restore(writer http.ResponseWriter, req *http.Request, t trashable) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	sql := "UPDATE " + t.table + " SET deleted_at=NULL, deleted_by=NULL, version=version+1 WHERE uid=$1 AND deleted_at IS NOT NULL"
+	if app.changeTrashState(writer, req, t, AuditRestore, false, sql) {
+		app.RenderJson(writer, http.StatusOK, nil)
+	}
+}
+
+// changeTrashState runs trashTransition for the item of the request in its own
+// transaction and renders the error response when it fails
+func (app *App) changeTrashState(writer http.ResponseWriter, req *http.Request, t trashable, action string, ifMatchRequired bool,
+	sql string, args ...interface{}) bool {
+	tx, err := app.db.Begin()
+	if err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed "+action+" "+t.resource)
+		return false
+	}
+	defer tx.Rollback()
+
+	ex := app.trashTransition(tx, req, t, mux.Vars(req)["id"], req.Header.Get("If-Match"), action, ifMatchRequired, sql, args...)
+	if ex != nil {
+		app.renderVersionedError(writer, *ex)
+		return false
+	}
+	if err = tx.Commit(); err != nil {
+		app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed "+action+" "+t.resource)
+		return false
+	}
+	return true
+}
+
+// trashTransition runs sql, which takes the item id as its last parameter,
+// and records the change as a revision and in the audit log. It fails with 404
+// when sql does not match the item. ifMatch is checked against the item
+// version, ifMatchRequired rejects an empty one.
+func (app *App) trashTransition(tx dbExecutor, req *http.Request, t trashable, id string, ifMatch string, action string,
+	ifMatchRequired bool, sql string, args ...interface{}) *ErrorResponse {
+	failed := func(err error) *ErrorResponse {
+		return &ErrorResponse{Status: http.StatusInternalServerError, Error: err, Message: "Failed " + action + " " + t.resource}
+	}
+	before, err := rowSnapshot(tx, t.table, id)
+	if err != nil && err != dbsql.ErrNoRows {
+		return failed(err)
+	}
+	var count int64
+	if err == nil {
+		var current struct {
+			Version int `json:"version"`
+		}
+		if err = json.Unmarshal(before, &current); err != nil {
+			return failed(err)
+		}
+		if ex := matchVersion(ifMatch, current.Version, ifMatchRequired); ex != nil {
+			return ex
+		}
+		result, err := tx.Exec(sql, append(args, id)...)
+		if err != nil {
+			return failed(err)
+		}
+		count, _ = result.RowsAffected()
+	}
+	if count == 0 {
+		return &ErrorResponse{Status: http.StatusNotFound, Error: NotFoundError, Message: fmt.Sprintf("Item [%s] not found", id)}
+	}
+	if t.revisions != nil {
+		if err = app.saveRequestRevision(tx, req, *t.revisions, id); err != nil {
+			return failed(err)
+		}
+	}
+
+	after, err := rowSnapshot(tx, t.table, id)
+	if err == nil {
+		err = app.recordAudit(tx, req, auditEntry{action: action, resourceType: t.resource, resourceID: id, before: before, after: after})
+	}
+	if err != nil {
+		return failed(err)
+	}
+	return nil
+}
+
+// RestoreNewsItem restores a deleted news item
+func (app *App) RestoreNewsItem(writer http.ResponseWriter, req *http.Request) {
+	app.restore(writer, req, trashNews)
+}
+
+// RestoreProjectItem restores a deleted project item
+func (app *App) RestoreProjectItem(writer http.ResponseWriter, req *http.Request) {
+	app.restore(writer, req, trashProjects)
+}
+
+// RestoreJob restores a deleted job application
+func (app *App) RestoreJob(writer http.ResponseWriter, req *http.Request) {
+	app.restore(writer, req, trashJobs)
+}
+
+// GetTrash lists the deleted items, most recently deleted first. ?type= limits
+// the list to news, project or job.
+func (app *App) GetTrash(writer http.ResponseWriter, req *http.Request) {
+	if !app.requireEditor(writer, req) {
+		return
+	}
+	resource := req.URL.Query().Get("type")
+	purgeAfter := app.conf.Trash.purgeAfter()
+
+	items := []TrashItemResponse{}
+	found := false
+	for _, t := range trashables {
+		if resource != "" && resource != t.resource {
+			continue
+		}
+		found = true
+
+		sql := "SELECT uid," + t.titleSQL + ",deleted_at,COALESCE(deleted_by,'') FROM " + t.table + " WHERE deleted_at IS NOT NULL"
+		rows, err := app.db.Query(sql)
+		if err != nil {
+			app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get trash")
+			return
+		}
+		for rows.Next() {
+			item := TrashItemResponse{Type: t.resource}
+			if err = rows.Scan(&item.ID, &item.Title, &item.DeletedAt, &item.DeletedBy); err != nil {
+				rows.Close()
+				app.RenderErrorResponse(writer, http.StatusInternalServerError, err, "Failed get trash")
+				return
+			}
+			item.PurgeAt = item.DeletedAt.Add(purgeAfter)
+			items = append(items, item)
+		}
+		rows.Close()
+	}
+	if !found {
+		app.RenderErrorResponse(writer, http.StatusBadRequest, fmt.Errorf("unknown type %q", resource), "Type must be news, project or job")
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	app.RenderJson(writer, http.StatusOK, items)
+}
+
+// purgeTrash permanently deletes the items that have been in the trash for
+// longer than the configured age
+func (app *App) purgeTrash(ctx context.Context) {
+	before := time.Now().Add(-app.conf.Trash.purgeAfter())
+	for _, t := range trashables {
+		count, err := app.purge(ctx, t, before)
+		if err != nil {
+			logrus.WithError(err).WithField("type", t.resource).Error("Failed to purge deleted items")
+			continue
+		}
+		if count > 0 {
+			logrus.WithFields(logrus.Fields{"type": t.resource, "count": count}).Info("Purged deleted items")
+		}
+	}
+}
+
+// purge deletes the items of t deleted before the given time, together with
+// their slug redirects
+func (app *App) purge(ctx context.Context, t trashable, before time.Time) (int64, error) {
+	tx, err := app.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	sql := "DELETE FROM content_revision WHERE resource_type=$1 AND resource_uid IN " +
+		"(SELECT uid FROM " + t.table + " WHERE deleted_at < $2)"
+	if _, err = tx.ExecContext(ctx, sql, t.resource, before); err != nil {
+		return 0, err
+	}
+	if t.slugType != "" {
+		sql = "DELETE FROM slug_redirect WHERE resource_type=$1 AND target_uid IN " +
+			"(SELECT uid FROM " + t.table + " WHERE deleted_at < $2)"
+		if _, err = tx.ExecContext(ctx, sql, t.slugType, before); err != nil {
+			return 0, err
+		}
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM "+t.table+" WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	count, _ := result.RowsAffected()
+	return count, tx.Commit()
+}
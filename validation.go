@@ -0,0 +1,418 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// ValidationConfig enables checking requests and responses against the OpenAPI spec
+type ValidationConfig struct {
+	// Requests rejects requests whose path, query or JSON body do not match the spec
+	Requests bool `yaml:"requests"`
+	// Responses checks JSON responses as well. It buffers every JSON response,
+	// so it is meant for development.
+	Responses bool `yaml:"responses"`
+}
+
+// ValidationIssue is a single mismatch between a request or response and the spec
+//
+//swagger:model ValidationIssue
+type ValidationIssue struct {
+	// In is path, query, body or response
+	In string `json:"in"`
+	// Name is the parameter name for path and query issues
+	Name string `json:"name,omitempty"`
+	// Pointer is the JSON pointer of the value for body and response issues
+	Pointer string `json:"pointer,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse lists the mismatches of a request or response
+//
+//swagger:response ValidationErrorResponse
+type ValidationErrorResponse struct {
+	Status  int               `json:"status"`
+	Message string            `json:"message"`
+	Errors  []ValidationIssue `json:"errors"`
+}
+
+// muxPathParam matches a mux path variable with a pattern, {rev:[0-9]+}
+var muxPathParam = regexp.MustCompile(`\{([^}:]+):[^}]+\}`)
+
+type specMedia struct {
+	Schema interface{} `json:"schema"`
+}
+
+type specParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+	Schema   interface{} `json:"schema"`
+}
+
+type specOperation struct {
+	Parameters  []specParameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]specMedia `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]specMedia `json:"content"`
+	} `json:"responses"`
+}
+
+// specValidator checks values against the schemas of the OpenAPI spec
+type specValidator struct {
+	paths   map[string]map[string]specOperation
+	schemas map[string]interface{}
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// newSpecValidator parses an OpenAPI document
+func newSpecValidator(spec string) (*specValidator, error) {
+	var document struct {
+		Paths      map[string]map[string]specOperation `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(spec), &document); err != nil {
+		return nil, err
+	}
+	return &specValidator{paths: document.Paths, schemas: document.Components.Schemas, patterns: map[string]*regexp.Regexp{}}, nil
+}
+
+// operation returns the spec operation of the route matched by req
+func (v *specValidator) operation(req *http.Request) (specOperation, bool) {
+	route := mux.CurrentRoute(req)
+	if route == nil {
+		return specOperation{}, false
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return specOperation{}, false
+	}
+	operation, ok := v.paths[muxPathParam.ReplaceAllString(template, "{$1}")][strings.ToLower(req.Method)]
+	return operation, ok
+}
+
+// validationMiddleware validates requests, and responses when enabled, against
+// the operation of the matched route. Requests that do not match are rejected
+// with 400, responses that do not match are replaced with a 500 listing the
+// mismatches.
+func (app *App) validationMiddleware(v *specValidator, conf ValidationConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+			operation, ok := v.operation(req)
+			if !ok {
+				next.ServeHTTP(writer, req)
+				return
+			}
+			if conf.Requests {
+				issues, err := v.validateRequest(req, operation)
+				if err != nil {
+					app.RenderErrorResponse(writer, http.StatusBadRequest, err, "Failed to read request body")
+					return
+				}
+				if len(issues) > 0 {
+					app.RenderJson(writer, http.StatusBadRequest, ValidationErrorResponse{
+						Status: http.StatusBadRequest, Message: "Request does not match the API spec", Errors: issues})
+					return
+				}
+			}
+			if !conf.Responses {
+				next.ServeHTTP(writer, req)
+				return
+			}
+
+			recorder := &validatingWriter{ResponseWriter: writer}
+			next.ServeHTTP(recorder, req)
+			if !recorder.buffered {
+				return
+			}
+			issues := v.validateResponse(operation, recorder.status, recorder.body.Bytes())
+			if len(issues) == 0 {
+				writer.WriteHeader(recorder.status)
+				writer.Write(recorder.body.Bytes())
+				return
+			}
+			logrus.WithField("request_id", requestID(req)).WithField("status", recorder.status).
+				WithField("errors", issues).Error("Response does not match the API spec")
+			writer.Header().Del("ETag")
+			app.RenderJson(writer, http.StatusInternalServerError, ValidationErrorResponse{
+				Status: http.StatusInternalServerError, Message: "Response does not match the API spec", Errors: issues})
+		})
+	}
+}
+
+// validatingWriter buffers JSON responses so they can be validated before they
+// are sent, other responses pass through
+type validatingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffered    bool
+	body        bytes.Buffer
+}
+
+func (w *validatingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader, w.status = true, status
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if mediaType == MediaJSON {
+		w.buffered = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *validatingWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffered {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush lets streaming handlers flush responses that are not buffered
+func (w *validatingWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok && !w.buffered {
+		flusher.Flush()
+	}
+}
+
+// validateRequest validates the path and query parameters and the JSON body of req
+func (v *specValidator) validateRequest(req *http.Request, operation specOperation) ([]ValidationIssue, error) {
+	var issues []ValidationIssue
+	vars := mux.Vars(req)
+	query := req.URL.Query()
+	for _, parameter := range operation.Parameters {
+		var values []string
+		switch parameter.In {
+		case "path":
+			values = []string{vars[parameter.Name]}
+		case "query":
+			values = query[parameter.Name]
+		default:
+			continue
+		}
+		if len(values) == 0 && parameter.Required {
+			issues = append(issues, ValidationIssue{In: parameter.In, Name: parameter.Name, Message: "is required"})
+		}
+		for _, value := range values {
+			for _, issue := range v.validateParameter(parameter.Schema, value) {
+				issue.In, issue.Name = parameter.In, parameter.Name
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	if operation.RequestBody == nil {
+		return issues, nil
+	}
+	media, ok := operation.RequestBody.Content[MediaJSON]
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if !ok || (mediaType != "" && mediaType != MediaJSON) {
+		return issues, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		return append(issues, ValidationIssue{In: "body", Message: "is required"}), nil
+	}
+	return append(issues, v.validateJSON("body", media.Schema, body)...), nil
+}
+
+// validateResponse validates a JSON response body against the schema of its
+// status. Statuses missing from the spec are not checked since handlers may
+// pass on statuses the generator cannot see, such as those of validators.
+func (v *specValidator) validateResponse(operation specOperation, status int, body []byte) []ValidationIssue {
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		return nil
+	}
+	media, ok := response.Content[MediaJSON]
+	if !ok || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	return v.validateJSON("response", media.Schema, body)
+}
+
+// validateJSON decodes data and validates it against schema
+func (v *specValidator) validateJSON(in string, schema interface{}, data []byte) []ValidationIssue {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []ValidationIssue{{In: in, Message: "invalid JSON: " + err.Error()}}
+	}
+	var issues []ValidationIssue
+	v.validateValue(schema, value, "", &issues)
+	for i := range issues {
+		issues[i].In = in
+	}
+	return issues
+}
+
+// validateParameter validates a path or query value, converting it to the
+// type of its schema first
+func (v *specValidator) validateParameter(schema interface{}, raw string) []ValidationIssue {
+	var value interface{} = raw
+	switch v.resolve(schema)["type"] {
+	case "integer", "number":
+		value = json.Number(raw)
+	case "boolean":
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []ValidationIssue{{Message: "must be a boolean"}}
+		}
+		value = parsed
+	}
+	var issues []ValidationIssue
+	v.validateValue(schema, value, "", &issues)
+	return issues
+}
+
+// resolve follows the $ref of a schema
+func (v *specValidator) resolve(schema interface{}) map[string]interface{} {
+	s, _ := schema.(map[string]interface{})
+	for s != nil {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			break
+		}
+		s, _ = v.schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]interface{})
+	}
+	return s
+}
+
+// validateValue appends the mismatches between value, decoded with UseNumber,
+// and schema to issues. pointer is the JSON pointer of value.
+func (v *specValidator) validateValue(schema interface{}, value interface{}, pointer string, issues *[]ValidationIssue) {
+	s := v.resolve(schema)
+	if len(s) == 0 {
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		*issues = append(*issues, ValidationIssue{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	if value == nil {
+		if s["nullable"] != true {
+			fail("must not be null")
+		}
+		return
+	}
+	if allOf, ok := s["allOf"].([]interface{}); ok {
+		for _, part := range allOf {
+			v.validateValue(part, value, pointer, issues)
+		}
+	}
+
+	switch s["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, known := properties[name]
+			if !known {
+				property = s["additionalProperties"]
+			}
+			if property == false {
+				*issues = append(*issues, ValidationIssue{Pointer: pointer + "/" + name, Message: "is not a known field"})
+				continue
+			}
+			v.validateValue(property, object[name], pointer+"/"+name, issues)
+		}
+		required, _ := s["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				*issues = append(*issues, ValidationIssue{Pointer: pointer + "/" + name.(string), Message: "is required"})
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		for i, item := range items {
+			v.validateValue(s["items"], item, pointer+"/"+strconv.Itoa(i), issues)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		switch s["format"] {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				fail("must be an RFC 3339 date-time")
+			}
+		case "byte":
+			if _, err := base64.StdEncoding.DecodeString(text); err != nil {
+				fail("must be base64 encoded")
+			}
+		}
+		if pattern, ok := s["pattern"].(string); ok && !v.pattern(pattern).MatchString(text) {
+			fail("must match %s", pattern)
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if _, err := number.Int64(); !ok || err != nil {
+			fail("must be an integer")
+		}
+	case "number":
+		number, ok := value.(json.Number)
+		if _, err := number.Float64(); !ok || err != nil {
+			fail("must be a number")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+		}
+	}
+}
+
+// pattern returns the compiled regular expression of a schema pattern
+func (v *specValidator) pattern(pattern string) *regexp.Regexp {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	compiled, ok := v.patterns[pattern]
+	if !ok {
+		compiled = regexp.MustCompile(pattern)
+		v.patterns[pattern] = compiled
+	}
+	return compiled
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// validationTestSpec is a small OpenAPI document for the validator tests
+const validationTestSpec = `{
+  "paths": {
+    "/item/{id}": {
+      "put": {
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}},
+          {"name": "draft", "in": "query", "schema": {"type": "boolean"}}
+        ],
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}},
+        "responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Item": {
+        "type": "object",
+        "required": ["name"],
+        "additionalProperties": false,
+        "properties": {
+          "name": {"type": "string", "pattern": "^[a-z]+$"},
+          "count": {"type": "integer"},
+          "score": {"type": "number"},
+          "active": {"type": "boolean"},
+          "note": {"type": "string", "nullable": true},
+          "created": {"type": "string", "format": "date-time"},
+          "image": {"type": "string", "format": "byte"},
+          "tags": {"type": "array", "items": {"type": "string"}},
+          "owner": {"$ref": "#/components/schemas/Owner"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+        }
+      },
+      "Owner": {
+        "allOf": [{"$ref": "#/components/schemas/Named"}],
+        "type": "object",
+        "properties": {"id": {"type": "integer"}}
+      },
+      "Named": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+    }
+  }
+}`
+
+func newTestValidator(t *testing.T) *specValidator {
+	v, err := newSpecValidator(validationTestSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestValidateValue(t *testing.T) {
+	v := newTestValidator(t)
+	item := map[string]interface{}{"$ref": "#/components/schemas/Item"}
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{"valid", `{"name":"a","count":2,"score":1.5,"active":true,"note":null,"created":"2020-01-02T03:04:05Z",
+			"image":"aGk=","tags":["x"],"owner":{"name":"o","id":1},"labels":{"k":"v"}}`, nil},
+		{"required field", `{"count":1}`, []string{"/name is required"}},
+		{"unknown field", `{"name":"a","secret":1}`, []string{"/secret is not a known field"}},
+		{"unknown fields sorted", `{"name":"a","z":1,"b":2}`, []string{"/b is not a known field", "/z is not a known field"}},
+		{"nullable", `{"name":"a","note":null}`, nil},
+		{"not nullable", `{"name":null}`, []string{"/name must not be null"}},
+		{"null document", `null`, []string{" must not be null"}},
+		{"integer", `{"name":"a","count":3}`, nil},
+		{"integer with fraction", `{"name":"a","count":3.5}`, []string{"/count must be an integer"}},
+		{"integer too large", `{"name":"a","count":92233720368547758070}`, []string{"/count must be an integer"}},
+		{"integer as string", `{"name":"a","count":"3"}`, []string{"/count must be an integer"}},
+		{"number accepts integers", `{"name":"a","score":3}`, nil},
+		{"number with exponent", `{"name":"a","score":1e3}`, nil},
+		{"number as string", `{"name":"a","score":"1.5"}`, []string{"/score must be a number"}},
+		{"boolean", `{"name":"a","active":"true"}`, []string{"/active must be a boolean"}},
+		{"pattern", `{"name":"A1"}`, []string{"/name must match ^[a-z]+$"}},
+		{"date-time", `{"name":"a","created":"2020-01-02"}`, []string{"/created must be an RFC 3339 date-time"}},
+		{"byte", `{"name":"a","image":"not base64!"}`, []string{"/image must be base64 encoded"}},
+		{"array items", `{"name":"a","tags":["x",1]}`, []string{"/tags/1 must be a string"}},
+		{"not an array", `{"name":"a","tags":"x"}`, []string{"/tags must be an array"}},
+		{"not an object", `[]`, []string{" must be an object"}},
+		{"allOf through ref", `{"name":"a","owner":{"id":"x"}}`, []string{"/owner/name is required", "/owner/id must be an integer"}},
+		{"additional properties schema", `{"name":"a","labels":{"k":1}}`, []string{"/labels/k must be a string"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range v.validateJSON("body", item, []byte(test.json)) {
+				if issue.In != "body" {
+					t.Errorf("issue %+v is not in body", issue)
+				}
+				got = append(got, issue.Pointer+" "+issue.Message)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateJSONInvalid(t *testing.T) {
+	issues := newTestValidator(t).validateJSON("body", map[string]interface{}{"type": "object"}, []byte(`{"name":`))
+	if len(issues) != 1 || !strings.HasPrefix(issues[0].Message, "invalid JSON") {
+		t.Errorf("got %+v", issues)
+	}
+}
+
+func TestValidateParameter(t *testing.T) {
+	v := newTestValidator(t)
+	tests := []struct {
+		schema string
+		raw    string
+		want   string
+	}{
+		{`{"type":"integer"}`, "12", ""},
+		{`{"type":"integer"}`, "1.5", "must be an integer"},
+		{`{"type":"integer"}`, "abc", "must be an integer"},
+		{`{"type":"number"}`, "1.5", ""},
+		{`{"type":"boolean"}`, "true", ""},
+		{`{"type":"boolean"}`, "yes", "must be a boolean"},
+		{`{"type":"string","pattern":"^[a-z-]+$"}`, "a-slug", ""},
+		{`{"type":"string","pattern":"^[a-z-]+$"}`, "Not a slug", "must match ^[a-z-]+$"},
+	}
+	for _, test := range tests {
+		var schema interface{}
+		if err := json.Unmarshal([]byte(test.schema), &schema); err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if issues := v.validateParameter(schema, test.raw); len(issues) > 0 {
+			got = issues[0].Message
+		}
+		if got != test.want {
+			t.Errorf("validateParameter(%s, %q) = %q, want %q", test.schema, test.raw, got, test.want)
+		}
+	}
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	app := &App{}
+	var response string
+	router := mux.NewRouter()
+	router.Use(app.validationMiddleware(newTestValidator(t), ValidationConfig{Requests: true, Responses: true}))
+	router.HandleFunc("/item/{id}", func(writer http.ResponseWriter, req *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte(response))
+	}).Methods("PUT")
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		response string
+		status   int
+		issues   []ValidationIssue
+	}{
+		{"valid", "/item/1?draft=true", `{"name":"a"}`, `{"name":"b"}`, http.StatusOK, nil},
+		{"path parameter", "/item/x", `{"name":"a"}`, `{"name":"b"}`, http.StatusBadRequest,
+			[]ValidationIssue{{In: "path", Name: "id", Message: "must be an integer"}}},
+		{"query parameter", "/item/1?draft=maybe", `{"name":"a"}`, `{"name":"b"}`, http.StatusBadRequest,
+			[]ValidationIssue{{In: "query", Name: "draft", Message: "must be a boolean"}}},
+		{"missing body", "/item/1", "", `{"name":"b"}`, http.StatusBadRequest,
+			[]ValidationIssue{{In: "body", Message: "is required"}}},
+		{"body", "/item/1", `{"name":"a","extra":true}`, `{"name":"b"}`, http.StatusBadRequest,
+			[]ValidationIssue{{In: "body", Pointer: "/extra", Message: "is not a known field"}}},
+		{"response", "/item/1", `{"name":"a"}`, `{"count":1}`, http.StatusInternalServerError,
+			[]ValidationIssue{{In: "response", Pointer: "/name", Message: "is required"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response = test.response
+			req := httptest.NewRequest("PUT", test.path, strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != test.status {
+				t.Fatalf("status %d, want %d: %s", recorder.Code, test.status, recorder.Body.String())
+			}
+			if test.issues == nil {
+				if recorder.Body.String() != test.response {
+					t.Errorf("body %s, want %s", recorder.Body.String(), test.response)
+				}
+				return
+			}
+			var got ValidationErrorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != test.status || !reflect.DeepEqual(got.Errors, test.issues) {
+				t.Errorf("got %+v, want issues %+v", got, test.issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// startWorker runs fn every interval on its own goroutine until the
+// application shuts down. fn is also called once right after start.
+func (app *App) startWorker(name string, interval time.Duration, fn func(ctx context.Context)) {
+	app.workers.Add(1)
+	go func() {
+		defer app.workers.Done()
+		logrus.WithField("worker", name).Info("Starting background worker")
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fn(app.workerCtx)
+			select {
+			case <-app.workerCtx.Done():
+				logrus.WithField("worker", name).Info("Background worker stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stopWorkers signals every background worker to stop and waits up to timeout
+// for them. It reports whether they all stopped.
+func (app *App) stopWorkers(timeout time.Duration) bool {
+	app.cancelWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		app.workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}